@@ -4,53 +4,209 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
-	"go.dalton.dog/stylish/internal/tui"
+	"go.dalton.dog/stylish/internal/background"
+	"go.dalton.dog/stylish/internal/profile"
+	"go.dalton.dog/stylish/internal/shell"
+	"go.dalton.dog/stylish/theme"
 )
 
+// useDircolors routes `apply` through the external `dircolors` binary instead of the native encoder
+var useDircolors bool
+
+// shellName is the shell to format the output for. Detected from $SHELL when empty
+var shellName string
+
+// applyFormat picks which environment variables `apply` exports
+var applyFormat string
+
+// applyFormats lists every value accepted by --format
+var applyFormats = []string{"ls", "eza", "bsd"}
+
+// backgroundName picks which of the styles' light or dark colors are used.
+// The theme's own background from its manifest is used when empty
+var backgroundName string
+
+// profileName is the terminal color profile `apply` encodes colors for
+var profileName string
+
+// matcherName picks how truecolor colors are matched to the xterm-256 palette
+var matcherName string
+
 func init() {
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(applyEightBitCmd)
+
+	for _, c := range []*cobra.Command{applyCmd, applyEightBitCmd} {
+		c.Flags().StringVar(&shellName, "shell", "", "Shell to format the output for ("+strings.Join(shell.Names, ", ")+"). Detected from $SHELL by default")
+		c.Flags().StringVar(&applyFormat, "format", "ls", "Output format ("+strings.Join(applyFormats, ", ")+"). eza also exports EZA_COLORS for eza's UI elements, bsd exports LSCOLORS for BSD/macOS ls")
+		c.Flags().BoolVar(&useDircolors, "dircolors", false, "Generate the output with the external dircolors binary and compare it against the native encoder")
+		c.Flags().StringVar(&backgroundName, "background", "", "Background to use the styles' colors for ("+strings.Join(background.Names, ", ")+"). auto asks the terminal. Defaults to the theme's own background")
+		c.Flags().StringVar(&matcherName, "matcher", string(theme.MatchRGB), "How colors are matched to the 256 color palette (rgb, ciede2000, oklab). ciede2000 and oklab match by how colors look")
+	}
+	applyCmd.Flags().StringVar(&profileName, "profile", profile.TrueColor, "Terminal color profile to encode colors for ("+strings.Join(profile.Names, ", ")+"). auto reads $COLORTERM and $TERM")
 }
 
 var applyCmd = &cobra.Command{
 	Use:   "apply",
-	Short: "Turns the theme's yaml files into a dircolors compatible format",
-	Long: `Takes the theme's yaml files and turns it 
-	into a dircolors compatible format. Should be used
+	Short: "Turns the theme's yaml files into an LS_COLORS export",
+	Long: `Takes the theme's yaml files and turns them 
+	into an LS_COLORS export. Should be used
 	with eval in your shell's init script.`,
-	Example: "eval $(stylish apply <theme>)",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Print(doApply(args[0]))
+	Example: `eval $(stylish apply <theme>)
+stylish apply --shell fish <theme> | source`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := doApply(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+		return nil
 	},
 }
 var applyEightBitCmd = &cobra.Command{
 
-	Use:     "apply-eightbit",
-	Short:   "apply command, but constrained to 8-bit colors",
-	Example: "eval $(stylish apply-eightbit <theme>)",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		tui.EightBitMode = true
-		fmt.Print(doApply(args[0]))
+	Use:        "apply-eightbit",
+	Short:      "apply command, but constrained to 8-bit colors",
+	Example:    "eval $(stylish apply-eightbit <theme>)",
+	Deprecated: "use `apply --profile 256` instead",
+	Args:       cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profileName = profile.ANSI256
+		output, err := doApply(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+		return nil
 	},
 }
 
-func doApply(themeName string) string {
-	theme := tui.GetTheme(themeName)
-	err := theme.GenerateDirColors()
+func doApply(themeName string) (string, error) {
+	t, err := theme.GetTheme(themeName)
+	if err != nil {
+		return "", err
+	}
+
+	sh := shell.Detect()
+	if shellName != "" {
+		sh, err = shell.Parse(shellName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !slices.Contains(applyFormats, applyFormat) {
+		return "", fmt.Errorf("unknown format %q, expected one of: %v", applyFormat, strings.Join(applyFormats, ", "))
+	}
+
+	if t, err = forBackground(t, backgroundName); err != nil {
+		return "", err
+	}
+
+	colorProfile, err := profile.Parse(profileName)
 	if err != nil {
-		log.Fatal(err)
+		return "", err
+	}
+	matcher, err := theme.ParseMatcher(matcherName)
+	if err != nil {
+		return "", err
+	}
+	t = t.WithProfile(colorProfile).WithMatcher(matcher)
+
+	if applyFormat == "bsd" {
+		value, warnings := t.BSDColors()
+		for _, warning := range warnings {
+			log.Warn(warning)
+		}
+		return shell.Export(sh, "CLICOLOR", "1") + shell.Export(sh, "LSCOLORS", value), nil
 	}
 
-	cmd := exec.Command("dircolors", filepath.Join(theme.Path, ".dircolors"))
+	value, err := t.LSColors()
+	if err != nil {
+		return "", err
+	}
+
+	if useDircolors {
+		external, err := doDircolors(t, sh)
+		if err != nil {
+			return "", err
+		}
+		if external != value {
+			log.Warn("Output from `dircolors` differs from the native encoder", "theme", t.Name)
+		}
+		value = external
+	}
+
+	output := shell.Export(sh, "LS_COLORS", value)
+
+	if applyFormat == "eza" {
+		ezaValue, err := t.EzaColors()
+		if err != nil {
+			return "", err
+		}
+		output += shell.Export(sh, "EZA_COLORS", ezaValue)
+	}
+
+	return output, nil
+}
+
+// forBackground swaps in the theme's colors for the named background, detecting it if it's `auto`
+func forBackground(t theme.Theme, name string) (theme.Theme, error) {
+	bg, err := resolveBackground(t, name)
+	if err != nil {
+		return t, err
+	}
+	return t.ForBackground(bg), nil
+}
+
+// resolveBackground turns a --background value into the background the theme is used on.
+// The theme's own background is used when name is empty.
+func resolveBackground(t theme.Theme, name string) (string, error) {
+	bg := t.Manifest.Background
+	if name != "" {
+		parsed, err := background.Parse(name)
+		if err != nil {
+			return "", err
+		}
+		bg = parsed
+	}
+
+	if bg == background.Auto {
+		bg = background.Detect(t.Manifest.Background)
+		log.Debug("Detected terminal background", "background", bg)
+	}
+	return bg, nil
+}
+
+// doDircolors writes the theme's .dircolors file and runs it through the external `dircolors` binary,
+// returning the raw LS_COLORS value it produced
+func doDircolors(t theme.Theme, sh shell.Shell) (string, error) {
+	err := t.GenerateDirColors()
+	if err != nil {
+		return "", err
+	}
+
+	flag := "--bourne-shell"
+	if sh == shell.Csh {
+		flag = "--c-shell"
+	}
+
+	cmd := exec.Command("dircolors", flag, filepath.Join(t.Path, ".dircolors"))
 	cmdOut, cmdErr := cmd.Output()
 	if cmdErr != nil {
-		log.Fatal(cmdErr.Error() + string(cmdOut))
+		return "", fmt.Errorf("theme %q: running dircolors: %w", t.Name, cmdErr)
 	}
-	return string(cmdOut)
+
+	output := strings.TrimSpace(string(cmdOut))
+	output = strings.TrimPrefix(output, "LS_COLORS='")
+	output = strings.TrimPrefix(output, "setenv LS_COLORS '")
+	output = strings.TrimSuffix(output, "';\nexport LS_COLORS")
+	return strings.TrimSuffix(output, "'"), nil
 }
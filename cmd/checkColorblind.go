@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.dalton.dog/stylish/internal/background"
+	"go.dalton.dog/stylish/theme"
+)
+
+// similarThreshold is the distance below which `check-colorblind` reports two styles as alike
+var similarThreshold float64
+
+func init() {
+	rootCmd.AddCommand(checkColorblindCmd)
+
+	checkColorblindCmd.Flags().StringVar(&simulationName, "simulate", "", "Only check one color vision deficiency ("+strings.Join(theme.Simulations, ", ")+"). Checks all of them by default")
+	checkColorblindCmd.Flags().Float64Var(&similarThreshold, "threshold", theme.MinSimulatedDistance, "CIEDE2000 distance below which two styles are reported as looking alike")
+	checkColorblindCmd.Flags().StringVar(&backgroundName, "background", "", "Background to check the styles' colors for ("+strings.Join(background.Names, ", ")+"). auto asks the terminal. Defaults to the theme's own background")
+}
+
+var checkColorblindCmd = &cobra.Command{
+	Use:   "check-colorblind <theme>",
+	Short: "Lists styles that look alike with a color vision deficiency",
+	Long: `Simulates how the theme's colors look with protanopia,
+	deuteranopia, tritanopia and achromatopsia, and lists pairs
+	of styles that can be told apart normally but not once
+	simulated. Exits non-zero if anything was found.`,
+	Example: `stylish check-colorblind <theme>
+stylish check-colorblind --simulate deuteranopia --threshold 15 <theme>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		bg, err := resolveBackground(t, backgroundName)
+		if err != nil {
+			return err
+		}
+		defaultFore, defaultBack := t.DefaultColors(bg)
+		t = t.ForBackground(bg)
+
+		simulations := theme.Simulations
+		if simulationName != "" {
+			simulation, err := theme.ParseSimulation(simulationName)
+			if err != nil {
+				return err
+			}
+			simulations = []string{simulation}
+		}
+
+		found := 0
+		for _, simulation := range simulations {
+			for _, pair := range t.SimilarStyles(simulation, defaultFore, defaultBack, similarThreshold) {
+				found++
+				fmt.Printf("%v: %v and %v look alike (distance %.1f)\n", simulation, pair.A, pair.B, pair.Distance)
+			}
+		}
+
+		if found > 0 {
+			return fmt.Errorf("theme %q: found %v pairs of styles that look alike", t.Name, found)
+		}
+		fmt.Printf("Theme %q has no styles that look alike\n", t.Name)
+		return nil
+	},
+}
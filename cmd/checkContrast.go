@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.dalton.dog/stylish/internal/background"
+	"go.dalton.dog/stylish/theme"
+)
+
+func init() {
+	rootCmd.AddCommand(checkContrastCmd)
+
+	checkContrastCmd.Flags().StringVar(&backgroundName, "background", "", "Background to check the styles' colors for ("+strings.Join(background.Names, ", ")+"). auto asks the terminal. Defaults to the theme's own background")
+}
+
+var checkContrastCmd = &cobra.Command{
+	Use:   "check-contrast <theme>",
+	Short: "Lists styles whose colors are hard to read",
+	Long: `Measures every style's foreground against its background
+	with both the WCAG 2 contrast ratio and APCA. Colors a style
+	leaves to the terminal are filled in from the theme's manifest.
+	Styles below either threshold are listed along with the
+	nearest fore that would pass, swapped for reversed styles.
+	Exits non-zero if anything was found.`,
+	Example: `stylish check-contrast <theme>
+stylish check-contrast --background light <theme>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		bg, err := resolveBackground(t, backgroundName)
+		if err != nil {
+			return err
+		}
+		defaultFore, defaultBack := t.DefaultColors(bg)
+
+		failed := 0
+		for _, style := range t.ForBackground(bg).Styles {
+			contrast, ok := style.Contrast(defaultFore, defaultBack)
+			if !ok || contrast.Pass() {
+				continue
+			}
+
+			failed++
+			fmt.Printf("%v: #%v on #%v - WCAG %.2f:1 (needs %v:1), APCA Lc %.0f (needs %v). Try fore #%v\n",
+				style.Name, contrast.Fore, contrast.Back, contrast.Ratio, theme.MinContrastRatio,
+				contrast.Lc, theme.MinAPCA, style.SuggestFore(contrast))
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("theme %q: found %v styles with low contrast", t.Name, failed)
+		}
+		fmt.Printf("Theme %q has no contrast problems\n", t.Name)
+		return nil
+	},
+}
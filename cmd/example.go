@@ -1,124 +1,258 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
+	"net"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
-	"syscall"
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
-	"go.dalton.dog/stylish/internal/tui"
+
+	"go.dalton.dog/stylish/internal/background"
+	"go.dalton.dog/stylish/internal/listing"
+	"go.dalton.dog/stylish/theme"
 )
 
+// treeOptions are the tree-style flags for `example`
+var treeOptions listing.TreeOptions
+
 func init() {
 	rootCmd.AddCommand(exampleCmd)
+
+	exampleCmd.Flags().IntVarP(&treeOptions.Depth, "depth", "L", 0, "Levels of the example directory to show. Unlimited by default")
+	exampleCmd.Flags().BoolVarP(&treeOptions.All, "all", "a", false, "Include hidden entries")
+	exampleCmd.Flags().BoolVar(&treeOptions.ASCII, "ascii", false, "Draw the tree with ASCII characters instead of box drawing characters")
+	exampleCmd.Flags().StringVar(&backgroundName, "background", "", "Background to use the styles' colors for ("+strings.Join(background.Names, ", ")+"). auto asks the terminal. Defaults to the theme's own background")
+	exampleCmd.Flags().StringVar(&simulationName, "simulate", "", "Show the colors as they look with a color vision deficiency ("+strings.Join(theme.Simulations, ", ")+")")
 }
 
 var exampleCmd = &cobra.Command{
-	Use:     "example",
-	Short:   "Generates an example directory with dummy files to showcase your theme.",
-	Example: "stylish example <theme>",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		binary, pathErr := exec.LookPath("tree")
-		if pathErr != nil {
-			log.Fatal(errors.New("Path err: " + pathErr.Error()))
+	Use:   "example <theme>",
+	Short: "Generates an example directory with dummy files to showcase your theme.",
+	Long: `Creates an example directory in the theme's folder with a
+	few dummy files for each style, then draws it as a tree
+	colored with the theme. No external tree is needed.`,
+	Example: `stylish example <theme>
+stylish example --ascii -L 1 <theme>
+stylish example --background light <theme>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := theme.GetTheme(args[0])
+		if err != nil {
+			return err
 		}
 
-		oldColors := os.Getenv("LS_COLORS")
-		defer os.Setenv("LS_COLORS", oldColors)
-
-		var themeName string
-		if len(os.Args) < 3 {
-			themeName = "default"
-		} else {
-			themeName = os.Args[2]
+		if err := createThemeExampleDir(t); err != nil {
+			return err
 		}
 
-		theme := tui.GetTheme(themeName)
-
-		createThemeExampleDir(theme)
-
-		output := doApply(themeName)
-		output = strings.TrimPrefix(output, "LS_COLORS='")
-		output = strings.TrimSpace(output)
-		output = strings.TrimSuffix(output, "';\nexport LS_COLORS")
-
-		os.Setenv("LS_COLORS", output)
-
-		execErr := syscall.Exec(binary, []string{"tree", filepath.Join(theme.Path, "example")}, os.Environ())
-		if execErr != nil {
-			log.Fatal(errors.New("Exec err: " + execErr.Error()))
+		shown, err := forBackground(t, backgroundName)
+		if err != nil {
+			return err
+		}
+		if shown, err = simulate(shown, simulationName); err != nil {
+			return err
 		}
+		colorizer, err := shown.Colorizer()
+		if err != nil {
+			return err
+		}
+
+		return listing.WriteTree(os.Stdout, filepath.Join(t.Path, "example"), colorizer, treeOptions)
 	},
 }
 
-func createThemeExampleDir(theme tui.Theme) {
-	outputDir := filepath.Join(theme.Path, "example")
-	err := os.MkdirAll(outputDir, 0755)
-	if err != nil {
-		log.Fatal(err)
+// createThemeExampleDir rebuilds the theme's example directory from scratch, so entries for
+// filetypes that have since been removed don't linger. Filenames are seeded from the theme and
+// style names, so the same theme always produces the same directory.
+func createThemeExampleDir(t theme.Theme) error {
+	outputDir := filepath.Join(t.Path, "example")
+	if err := os.RemoveAll(outputDir); err != nil {
+		return fmt.Errorf("theme %q: clearing example dir: %w", t.Name, err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("theme %q: creating example dir: %w", t.Name, err)
 	}
 
-	for _, style := range theme.Styles {
+	for _, style := range t.Styles {
 		styleDir := filepath.Join(outputDir, style.Name)
 		err := os.MkdirAll(styleDir, 0755)
 		if err != nil {
-			log.Fatal(err)
+			return fmt.Errorf("theme %q: creating example dir for style %q: %w", t.Name, style.Name, err)
 		}
 
-		for i, fileType := range style.FileTypes {
-			if i >= 3 {
-				break
+		fileTypes, err := style.ParseFileTypes()
+		if err != nil {
+			return err
+		}
+
+		seed := fnv.New64a()
+		seed.Write([]byte(t.Name + "/" + style.Name))
+		rng := rand.New(rand.NewSource(int64(seed.Sum64())))
+
+		created := 0
+		keywords := make(map[string]bool)
+		for _, fileType := range fileTypes {
+			if fileType.Kind == theme.KindKeyword {
+				// Aliases like LINK and SYMLINK make the same file, which only needs creating once
+				name := keywordExamples[theme.Keywords[fileType.Value]]
+				if name == "" || keywords[name] {
+					continue
+				}
+				keywords[name] = true
+				if err := createKeywordExample(theme.Keywords[fileType.Value], styleDir); err != nil {
+					return fmt.Errorf("theme %q: creating %v example for style %q: %w", t.Name, fileType.Value, style.Name, err)
+				}
+				continue
 			}
-			if fileType[0] != '.' {
+
+			if created >= 3 {
 				continue
 			}
-			// Check if a file with this filetype already exists
-			exists, err := fileTypeExistsInDir(fileType, styleDir)
-			if err != nil {
-				log.Fatal(err)
+			exampleName := fileType.ExampleName(exampleBaseName(rng))
+			if exampleName == "" {
+				continue
 			}
-			if exists {
+			created++
+
+			// Filename entries can repeat the same name, which only needs creating once
+			if _, err := os.Lstat(filepath.Join(styleDir, exampleName)); err == nil {
 				continue
 			}
-			randFileName := fmt.Sprintf("%v%v", randomFileName(), fileType)
-			fileName := filepath.Join(styleDir, randFileName)
-			file, err := os.Create(fileName)
+			file, err := os.Create(filepath.Join(styleDir, exampleName))
 			if err != nil {
-				log.Fatal(err)
+				return fmt.Errorf("theme %q: creating example file for style %q: %w", t.Name, style.Name, err)
 			}
 			file.Close()
 		}
 	}
+
+	return nil
+}
+
+// keywordExamples are the names of the files made for each LS_COLORS code. Block and character
+// devices, doors, and capabilities can't be made without extra privileges, so they're left out
+// along with codes that don't color files at all.
+var keywordExamples = map[string]string{
+	"no": "file",
+	"fi": "file",
+	"di": "directory",
+	"ln": "link",
+	"or": "orphan",
+	"mi": "orphan",
+	"pi": "pipe",
+	"so": "socket",
+	"ex": "executable",
+	"su": "setuid",
+	"sg": "setgid",
+	"st": "sticky",
+	"ow": "other-writable",
+	"tw": "sticky-other-writable",
+	"mh": "hardlink",
+}
+
+// createKeywordExample creates a real file of the system type an LS_COLORS code colors inside of dir
+func createKeywordExample(code, dir string) error {
+	path := filepath.Join(dir, keywordExamples[code])
+
+	switch code {
+	case "no", "fi":
+		return createExampleFile(path, 0644)
+	case "di":
+		return createExampleDir(path, 0755)
+	case "ln":
+		return os.Symlink(".", path)
+	case "or", "mi":
+		return os.Symlink("missing", path)
+	case "pi":
+		return createExampleFifo(path)
+	case "so":
+		return createExampleSocket(path)
+	case "ex":
+		return createExampleFile(path, 0755)
+	case "su":
+		return createExampleFile(path, 0755|os.ModeSetuid)
+	case "sg":
+		return createExampleFile(path, 0755|os.ModeSetgid)
+	case "st":
+		return createExampleDir(path, 0755|os.ModeSticky)
+	case "ow":
+		return createExampleDir(path, 0777)
+	case "tw":
+		return createExampleDir(path, 0777|os.ModeSticky)
+	case "mh":
+		if err := createExampleFile(path, 0644); err != nil {
+			return err
+		}
+		return os.Link(path, path+"-2")
+	}
+	return nil
 }
 
-func fileTypeExistsInDir(fileType, dirPath string) (bool, error) {
-	entries, err := os.ReadDir(dirPath)
+// createExampleFile creates an empty file, setting its mode afterwards so the umask can't strip any bits
+func createExampleFile(path string, mode os.FileMode) error {
+	file, err := os.Create(path)
 	if err != nil {
-		return false, err
+		return err
 	}
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			if filepath.Ext(entry.Name()) == fileType {
-				return true, nil
+	file.Close()
+	return os.Chmod(path, mode)
+}
+
+// createExampleDir creates a directory, setting its mode afterwards so the umask can't strip any bits
+func createExampleDir(path string, mode os.FileMode) error {
+	if err := os.Mkdir(path, 0755); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
+
+// createExampleSocket leaves a Unix socket at path. Socket addresses are limited to around 100
+// bytes, so when path is too long the socket is bound by its base name from inside its directory.
+// This changes the working directory of the whole process for a moment, which is safe as nothing
+// else runs alongside `example`. If the socket still can't be made, it's skipped with a warning.
+func createExampleSocket(path string) error {
+	bind := func(name string) error {
+		listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: name, Net: "unix"})
+		if err != nil {
+			return err
+		}
+		listener.SetUnlinkOnClose(false)
+		return listener.Close()
+	}
+
+	err := bind(path)
+	if err == nil {
+		return nil
+	}
+
+	if wd, wdErr := os.Getwd(); wdErr == nil {
+		if os.Chdir(filepath.Dir(path)) == nil {
+			err = bind(filepath.Base(path))
+			if wdErr := os.Chdir(wd); wdErr != nil {
+				return wdErr
+			}
+			if err == nil {
+				return nil
 			}
 		}
 	}
-	return false, nil
+
+	log.Warn("Skipping the example socket", "path", path, "err", err)
+	return nil
 }
 
-func randomFileName() string {
+// exampleBaseName generates a filename from rng, to be combined with a filetype's example name
+func exampleBaseName(rng *rand.Rand) string {
 	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	n := rand.Intn(16) + 1 // Random length between 1 and 16
+	n := rng.Intn(16) + 1 // Random length between 1 and 16
 	result := make([]byte, n)
 	for i := range result {
-		result[i] = letters[rand.Intn(len(letters))]
+		result[i] = letters[rng.Intn(len(letters))]
 	}
 	return string(result)
 }
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package cmd
+
+import "github.com/charmbracelet/log"
+
+// createExampleFifo would leave a named pipe at path, but other systems can't make them
+// in the filesystem, so the pipe is skipped with a warning like an unmakeable socket
+func createExampleFifo(path string) error {
+	log.Warn("Skipping the example pipe", "path", path)
+	return nil
+}
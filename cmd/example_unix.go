@@ -0,0 +1,10 @@
+//go:build unix
+
+package cmd
+
+import "syscall"
+
+// createExampleFifo leaves a named pipe at path
+func createExampleFifo(path string) error {
+	return syscall.Mkfifo(path, 0644)
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// importValue is a raw LS_COLORS value to import instead of reading a file
+var importValue string
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importValue, "ls-colors", "", "Raw LS_COLORS value to import instead of a file")
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <theme> [file]",
+	Short: "Creates a new theme from a dircolors file or LS_COLORS value",
+	Long: `Parses a dircolors database or an LS_COLORS value and writes
+	it out as a new theme. Filetypes sharing the same colors and
+	attributes are grouped together into a single style.
+	If no file is given, the current $LS_COLORS is imported.
+	Pass - as the file to read from stdin.`,
+	Example: `stylish import mine ~/.dircolors
+stylish import current
+dircolors -b | stylish import generated -`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := readImportSource(args[1:])
+		if err != nil {
+			return err
+		}
+
+		t, err := theme.Import(args[0], data)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Imported %v styles into %v\n", len(t.Styles), t.Path)
+		return nil
+	},
+}
+
+// readImportSource returns the data to import from the --ls-colors flag, a file, stdin, or $LS_COLORS
+func readImportSource(args []string) (string, error) {
+	if importValue != "" {
+		return importValue, nil
+	}
+
+	if len(args) == 0 {
+		return theme.ReadImportSource("")
+	}
+
+	if args[0] == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("reading stdin: %w", err)
+		}
+		return string(data), nil
+	}
+
+	return theme.ReadImportSource(args[0])
+}
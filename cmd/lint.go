@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <theme>",
+	Short: "Checks a theme for conflicting and invalid entries",
+	Long: `Reports filetypes claimed by more than one style (and which
+	style wins), filetypes dircolors would reject, malformed hex
+	codes, styles with no filetypes, and style files whose theme
+	or name fields disagree with where they're saved.
+	Exits non-zero if anything was found.`,
+	Example: "stylish lint <theme>",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+
+		problems := t.Lint()
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("theme %q: found %v problems", t.Name, len(problems))
+		}
+		fmt.Printf("Theme %q has no problems\n", t.Name)
+		return nil
+	},
+}
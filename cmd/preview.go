@@ -1,44 +1,81 @@
 package cmd
 
 import (
-	"errors"
 	"os"
-	"os/exec"
 	"strings"
-	"syscall"
 
-	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"go.dalton.dog/stylish/internal/background"
+	"go.dalton.dog/stylish/internal/listing"
+	"go.dalton.dog/stylish/theme"
 )
 
+// listOptions are the ls-style flags shared by `preview`
+var listOptions listing.Options
+
+// simulationName is the color vision deficiency to show the theme's colors with, if any
+var simulationName string
+
 func init() {
 	rootCmd.AddCommand(previewCmd)
+
+	previewCmd.Flags().BoolVarP(&listOptions.All, "all", "a", false, "Include hidden entries")
+	previewCmd.Flags().BoolVarP(&listOptions.Long, "long", "l", false, "Use a long listing with permissions, owner, size and modification time")
+	previewCmd.Flags().BoolVarP(&listOptions.Recursive, "recursive", "R", false, "List subdirectories recursively")
+	previewCmd.Flags().StringVar(&backgroundName, "background", "", "Background to use the styles' colors for ("+strings.Join(background.Names, ", ")+"). auto asks the terminal. Defaults to the theme's own background")
+	previewCmd.Flags().StringVar(&simulationName, "simulate", "", "Show the colors as they look with a color vision deficiency ("+strings.Join(theme.Simulations, ", ")+")")
 }
 
 var previewCmd = &cobra.Command{
-	Use:     "preview",
-	Short:   "Shows how your current directory would look with the given theme.",
-	Example: "stylish preview <theme>",
-	Args:    cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		oldColors := os.Getenv("LS_COLORS")
-		defer os.Setenv("LS_COLORS", oldColors)
-
-		output := doApply(args[0])
-		output = strings.TrimPrefix(output, "LS_COLORS='")
-		output = strings.TrimSpace(output)
-		output = strings.TrimSuffix(output, "';\nexport LS_COLORS")
-
-		os.Setenv("LS_COLORS", output)
-
-		binary, pathErr := exec.LookPath("ls")
-		if pathErr != nil {
-			log.Fatal(errors.New("Path err: " + pathErr.Error()))
+	Use:   "preview <theme> [dir]",
+	Short: "Shows how a directory would look with the given theme.",
+	Long: `Lists a directory (the current one by default) with the
+	given theme applied. Entries are classified the same way
+	GNU ls does it, so no ls is needed.`,
+	Example: `stylish preview <theme>
+stylish preview -la <theme> ~/Downloads`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := theme.GetTheme(args[0])
+		if err != nil {
+			return err
+		}
+		if t, err = forBackground(t, backgroundName); err != nil {
+			return err
+		}
+		if t, err = simulate(t, simulationName); err != nil {
+			return err
 		}
 
-		execErr := syscall.Exec(binary, []string{"ls", "--color=auto"}, os.Environ())
-		if execErr != nil {
-			log.Fatal(errors.New("Exec err: " + execErr.Error()))
+		colorizer, err := t.Colorizer()
+		if err != nil {
+			return err
 		}
+
+		dir := "."
+		if len(args) > 1 {
+			dir = args[1]
+		}
+
+		// Like ls, only lay out columns when writing to a terminal
+		if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			listOptions.Width = width
+		}
+
+		return listing.Write(os.Stdout, dir, colorizer, listOptions)
 	},
 }
+
+// simulate recolors the theme for the named color vision deficiency. An empty name leaves it as-is.
+func simulate(t theme.Theme, name string) (theme.Theme, error) {
+	if name == "" {
+		return t, nil
+	}
+	simulation, err := theme.ParseSimulation(name)
+	if err != nil {
+		return t, err
+	}
+	return t.Simulate(simulation), nil
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"go.dalton.dog/stylish/internal/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -11,18 +14,26 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "stylish",
 	Short: "stylish is a simple and intuitive path to a prettier ls experience",
-	Run: func(cmd *cobra.Command, args []string) {
+	// Arguments have been validated by the time this runs, so any errors
+	// from here on out aren't usage mistakes and don't need the usage text
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		cmd.SilenceUsage = true
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		program := tea.NewProgram(tui.NewLandingModel(), tea.WithAltScreen())
 		if _, err := program.Run(); err != nil {
-			log.Fatalf("Error running program: %v\n", err)
+			return fmt.Errorf("running program: %w", err)
 		}
+		return nil
 	},
 }
 
 func Execute() {
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
+	rootCmd.SilenceErrors = true
 
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+		log.Error(err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// styleColors are the --fore and --back values for `style set`
+var styleColors struct {
+	fore, back string
+}
+
+// styleAttrs are the attribute flags for `style set`, in the same order as the editor's 1-9 keys
+var styleAttrs = []struct {
+	flag  string
+	usage string
+	value bool
+	set   func(*theme.Style, bool)
+}{
+	{"bold", "Bold", false, func(s *theme.Style, on bool) { s.Bold = on }},
+	{"under", "Underline", false, func(s *theme.Style, on bool) { s.Under = on }},
+	{"blink", "Blink", false, func(s *theme.Style, on bool) { s.Blink = on }},
+	{"italic", "Italic", false, func(s *theme.Style, on bool) { s.Italic = on }},
+	{"dim", "Dim", false, func(s *theme.Style, on bool) { s.Dim = on }},
+	{"reverse", "Swap the foreground and background", false, func(s *theme.Style, on bool) { s.Reverse = on }},
+	{"strike", "Strikethrough", false, func(s *theme.Style, on bool) { s.Strike = on }},
+	{"hidden", "Hide the text", false, func(s *theme.Style, on bool) { s.Hidden = on }},
+	{"overline", "Overline", false, func(s *theme.Style, on bool) { s.Overline = on }},
+}
+
+func init() {
+	rootCmd.AddCommand(styleCmd)
+	styleCmd.AddCommand(styleAddCmd, styleRmCmd, styleRenameCmd, styleCopyCmd, styleSetCmd, styleFileTypesCmd)
+	styleFileTypesCmd.AddCommand(styleFileTypesAddCmd, styleFileTypesRmCmd)
+
+	styleSetCmd.Flags().StringVar(&styleColors.fore, "fore", "", "Foreground as a hex code or $palette reference. Empty uses the terminal's default")
+	styleSetCmd.Flags().StringVar(&styleColors.back, "back", "", "Background as a hex code or $palette reference. Empty uses the terminal's default")
+	for i := range styleAttrs {
+		attr := &styleAttrs[i]
+		styleSetCmd.Flags().BoolVar(&attr.value, attr.flag, false, attr.usage+". Use --"+attr.flag+"=false to turn it off")
+	}
+}
+
+var styleCmd = &cobra.Command{
+	Use:   "style",
+	Short: "Edits a theme's styles without the TUI",
+	Long: `Adds, removes, renames, copies and edits styles, for
+	scripts that set up themes. Everything is validated the
+	same way the editor does it, and nothing is saved if
+	anything is invalid.`,
+}
+
+var styleAddCmd = &cobra.Command{
+	Use:     "add <theme> <style>",
+	Short:   "Adds a new, empty style to a theme",
+	Example: `stylish style add <theme> "Go Code"`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		return t.AddStyle(t.NewStyle(args[1]))
+	},
+}
+
+var styleRmCmd = &cobra.Command{
+	Use:   "rm <theme> <style>",
+	Short: "Removes a style from a theme",
+	Long: `Removes a style from a theme. Removing a style the theme
+	inherits also adds it to the removals in theme.yaml.`,
+	Example: `stylish style rm <theme> Audio`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		if _, err := t.GetStyle(args[1]); err != nil {
+			return err
+		}
+		return t.RemoveStyle(args[1])
+	},
+}
+
+var styleRenameCmd = &cobra.Command{
+	Use:     "rename <theme> <style> <new name>",
+	Short:   "Renames one of a theme's styles",
+	Example: `stylish style rename <theme> Audio Music`,
+	Args:    cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		return t.RenameStyle(args[1], args[2])
+	},
+}
+
+var styleCopyCmd = &cobra.Command{
+	Use:     "copy <theme> <style> <new name>",
+	Short:   "Copies one of a theme's styles under a new name",
+	Example: `stylish style copy <theme> Audio Podcasts`,
+	Args:    cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		style, err := t.GetStyle(args[1])
+		if err != nil {
+			return err
+		}
+		return t.AddStyle(theme.CopyStyle(*style, args[2]))
+	},
+}
+
+var styleSetCmd = &cobra.Command{
+	Use:   "set <theme> <style>",
+	Short: "Sets a style's colors and attributes",
+	Long: `Sets a style's colors and attributes. Only the flags that
+	are given are changed, so --fore alone keeps everything else.`,
+	Example: `stylish style set <theme> Audio --fore FFADAD --bold
+stylish style set <theme> Audio --back "" --bold=false
+stylish style set <theme> Audio --fore '$accent'`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		style, err := t.GetStyle(args[1])
+		if err != nil {
+			return err
+		}
+
+		colors := []struct {
+			flag  string
+			value string
+			set   func(string)
+		}{
+			{"fore", styleColors.fore, style.SetFore},
+			{"back", styleColors.back, style.SetBack},
+		}
+		for _, color := range colors {
+			if !cmd.Flags().Changed(color.flag) {
+				continue
+			}
+			value := strings.TrimPrefix(color.value, "#")
+			if err := style.ValidColor(value); err != nil {
+				return fmt.Errorf("style %q: --%v %q: %w", style.Name, color.flag, color.value, err)
+			}
+			color.set(value)
+		}
+
+		for _, attr := range styleAttrs {
+			if cmd.Flags().Changed(attr.flag) {
+				attr.set(style, attr.value)
+			}
+		}
+
+		return style.SaveStyle()
+	},
+}
+
+var styleFileTypesCmd = &cobra.Command{
+	Use:   "filetypes",
+	Short: "Adds or removes a style's filetypes",
+}
+
+var styleFileTypesAddCmd = &cobra.Command{
+	Use:   "add <theme> <style> <filetype>...",
+	Short: "Adds filetypes to a style",
+	Long: `Adds filetypes to a style, using the same entries as the
+	editor (.go, *.tar.gz, file:Makefile, DIR, eza:ur, ...).
+	Filetypes the style already has are skipped.`,
+	Example: `stylish style filetypes add <theme> Audio .opus .m4a`,
+	Args:    cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		style, err := t.GetStyle(args[1])
+		if err != nil {
+			return err
+		}
+
+		for _, fileType := range args[2:] {
+			if !slices.Contains(style.FileTypes, fileType) {
+				style.FileTypes = append(style.FileTypes, fileType)
+			}
+		}
+		return style.SaveStyle()
+	},
+}
+
+var styleFileTypesRmCmd = &cobra.Command{
+	Use:     "rm <theme> <style> <filetype>...",
+	Short:   "Removes filetypes from a style",
+	Example: `stylish style filetypes rm <theme> Audio .opus .m4a`,
+	Args:    cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := existingTheme(args[0])
+		if err != nil {
+			return err
+		}
+		style, err := t.GetStyle(args[1])
+		if err != nil {
+			return err
+		}
+
+		for _, fileType := range args[2:] {
+			i := slices.Index(style.FileTypes, fileType)
+			if i < 0 {
+				return fmt.Errorf("style %q doesn't have filetype %v", style.Name, fileType)
+			}
+			style.FileTypes = slices.Delete(style.FileTypes, i, i+1)
+		}
+		return style.SaveStyle()
+	},
+}
+
+// existingTheme loads a theme that's already in the config folder. GetTheme would create a
+// missing theme, which a typo in a script shouldn't do.
+func existingTheme(name string) (theme.Theme, error) {
+	if _, err := os.Stat(filepath.Join(theme.ThemeConfigFolder, name)); err != nil {
+		return theme.Theme{}, fmt.Errorf("theme %q: %w", name, err)
+	}
+	return theme.GetTheme(name)
+}
@@ -0,0 +1,84 @@
+// Package background works out whether the terminal stylish is running in has a light or dark background
+package background
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"golang.org/x/term"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// Auto asks Detect to query the terminal instead of naming a background
+const Auto = "auto"
+
+// Names lists every value accepted by Parse
+var Names = []string{theme.BackgroundLight, theme.BackgroundDark, Auto}
+
+// Parse checks that name is a background stylish knows about
+func Parse(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case theme.BackgroundLight:
+		return theme.BackgroundLight, nil
+	case theme.BackgroundDark:
+		return theme.BackgroundDark, nil
+	case Auto:
+		return Auto, nil
+	}
+
+	return "", fmt.Errorf("unknown background %q, expected one of: %v", name, strings.Join(Names, ", "))
+}
+
+// Detect asks the terminal for its background color with an OSC 11 query. The terminal is reached
+// through /dev/tty, as stdout is usually captured by `eval`. Terminals that don't answer fall back
+// to $COLORFGBG, then to dark like termenv does. Without any terminal, fallback is used instead.
+//
+// The query is only sent when stdin is a terminal too. Scripts and pipes can still have a
+// controlling terminal, but nothing there to answer, and termenv waits 5 seconds for a reply.
+func Detect(fallback string) string {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fromEnv(fallback)
+	}
+
+	if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+		defer tty.Close()
+		// termenv checks $COLORFGBG itself when the terminal doesn't answer
+		return fromColor(termenv.NewOutput(tty).BackgroundColor())
+	}
+
+	return fromEnv(fallback)
+}
+
+// fromEnv reads the background out of $COLORFGBG, using fallback when it isn't set
+func fromEnv(fallback string) string {
+	if bg, ok := fromColorFGBG(os.Getenv("COLORFGBG")); ok {
+		return bg
+	}
+	return fallback
+}
+
+// fromColorFGBG reads the background out of a $COLORFGBG value like `15;0`, where the last
+// field is the ANSI index of the background color
+func fromColorFGBG(value string) (string, bool) {
+	fields := strings.Split(value, ";")
+	if len(fields) < 2 {
+		return "", false
+	}
+
+	index, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || index < 0 || index > 15 {
+		return "", false
+	}
+	return fromColor(termenv.ANSIColor(index)), true
+}
+
+func fromColor(color termenv.Color) string {
+	if _, _, l := termenv.ConvertToRGB(color).Hsl(); l >= 0.5 {
+		return theme.BackgroundLight
+	}
+	return theme.BackgroundDark
+}
@@ -0,0 +1,162 @@
+// Package listing lists directories natively, classifying each entry the way GNU ls does
+// so that previews look the same with or without coreutils installed.
+package listing
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// Entry is a single file found while scanning a directory
+type Entry struct {
+	Name string
+	Path string
+	// Info describes the entry itself, without following symlinks
+	Info fs.FileInfo
+
+	// Target is where a symlink points, and TargetInfo describes what's there.
+	// TargetInfo is nil for orphaned symlinks.
+	Target     string
+	TargetInfo fs.FileInfo
+}
+
+// Scan reads every entry of dir, sorted by name. Hidden entries, along with `.` and `..`,
+// are only included when all is set.
+func Scan(dir string, all bool) ([]Entry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("listing %v: %w", dir, err)
+	}
+
+	var names []string
+	if all {
+		names = append(names, ".", "..")
+	}
+	for _, dirEntry := range dirEntries {
+		if !all && dirEntry.Name()[0] == '.' {
+			continue
+		}
+		names = append(names, dirEntry.Name())
+	}
+	sort.Strings(names)
+
+	entries := make([]Entry, 0, len(names))
+	for _, name := range names {
+		entry, err := Stat(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		entry.Name = name
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Stat reads a single entry at path
+func Stat(path string) (Entry, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("listing %v: %w", path, err)
+	}
+
+	entry := Entry{Name: filepath.Base(path), Path: path, Info: info}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if entry.Target, err = os.Readlink(path); err != nil {
+			return Entry{}, fmt.Errorf("listing %v: %w", path, err)
+		}
+		// A failed stat just means the link is orphaned
+		entry.TargetInfo, _ = os.Stat(path)
+	}
+
+	return entry, nil
+}
+
+// Code classifies the entry into the two-letter LS_COLORS code it would be colored with.
+// Like GNU ls, the more specific codes (setuid, sticky, orphan...) are only used when
+// the theme colors them, otherwise the entry falls back to the general code.
+func (e Entry) Code(c theme.Colorizer) string {
+	return classify(e.Info, e.TargetInfo != nil, c)
+}
+
+// TargetCode classifies what a symlink points to. Missing targets are `mi` when the theme
+// colors it, and `or` otherwise.
+func (e Entry) TargetCode(c theme.Colorizer) string {
+	if e.TargetInfo == nil {
+		if _, ok := c.Indicator("mi"); ok {
+			return "mi"
+		}
+		return "or"
+	}
+	return classify(e.TargetInfo, true, c)
+}
+
+func classify(info fs.FileInfo, linkOK bool, c theme.Colorizer) string {
+	colored := func(code string) bool {
+		_, ok := c.Indicator(code)
+		return ok
+	}
+
+	mode := info.Mode()
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		if !linkOK && colored("or") {
+			return "or"
+		}
+		return "ln"
+	case mode.IsDir():
+		sticky := mode&fs.ModeSticky != 0
+		otherWritable := mode.Perm()&0o002 != 0
+		if sticky && otherWritable && colored("tw") {
+			return "tw"
+		} else if otherWritable && colored("ow") {
+			return "ow"
+		} else if sticky && colored("st") {
+			return "st"
+		}
+		return "di"
+	case mode&fs.ModeNamedPipe != 0:
+		return "pi"
+	case mode&fs.ModeSocket != 0:
+		return "so"
+	case mode&fs.ModeCharDevice != 0:
+		return "cd"
+	case mode&fs.ModeDevice != 0:
+		return "bd"
+	case mode.IsRegular():
+		if mode&fs.ModeSetuid != 0 && colored("su") {
+			return "su"
+		} else if mode&fs.ModeSetgid != 0 && colored("sg") {
+			return "sg"
+		} else if mode.Perm()&0o111 != 0 && colored("ex") {
+			return "ex"
+		} else if links(info) > 1 && colored("mh") {
+			return "mh"
+		}
+		return "fi"
+	}
+	return "or"
+}
+
+// fileStat holds the details of a file that only the system's stat call knows about
+type fileStat struct {
+	nlink    uint64
+	uid, gid string
+	// blocks is the number of 512 byte blocks the file takes up
+	blocks int64
+	// major and minor identify the device a device file stands for
+	major, minor uint64
+}
+
+// links returns the number of hard links to a file, or 1 if it can't be determined
+func links(info fs.FileInfo) uint64 {
+	if stat, ok := statOf(info); ok {
+		return stat.nlink
+	}
+	return 1
+}
@@ -0,0 +1,328 @@
+package listing
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// Options control how a listing is rendered
+type Options struct {
+	All       bool // Include hidden entries, along with `.` and `..`
+	Long      bool // One entry per line with permissions, owner, size and modification time
+	Recursive bool // List every subdirectory after its parent
+	Width     int  // Width available for the column layout. Entries go one per line when < 1
+}
+
+// Write lists dir into w, colored with c. Recursive listings print a header before each
+// directory, the same as `ls -R`.
+func Write(w io.Writer, dir string, c theme.Colorizer, opts Options) error {
+	p := &painter{c: c}
+	return p.write(w, dir, opts)
+}
+
+func (p *painter) write(w io.Writer, dir string, opts Options) error {
+	entries, err := Scan(dir, opts.All)
+	if err != nil {
+		return err
+	}
+
+	if opts.Recursive {
+		fmt.Fprintf(w, "%v:\n", dir)
+	}
+	fmt.Fprint(w, p.render(entries, opts))
+
+	if !opts.Recursive {
+		return nil
+	}
+	for _, entry := range entries {
+		if !entry.Info.IsDir() || entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+		fmt.Fprintln(w)
+		// Not filepath.Join, so headers read `./sub` like they do for ls
+		if err := p.write(w, strings.TrimSuffix(dir, "/")+"/"+entry.Name, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render formats already scanned entries, either in columns or as a long listing
+func Render(entries []Entry, c theme.Colorizer, opts Options) string {
+	p := &painter{c: c}
+	return p.render(entries, opts)
+}
+
+func (p *painter) render(entries []Entry, opts Options) string {
+	if opts.Long {
+		return p.renderLong(entries)
+	}
+	return p.renderColumns(entries, opts)
+}
+
+// painter writes escape sequences byte for byte the way GNU ls does, including the reset
+// written before the first color and the NORMAL (`no`) color around each entry
+type painter struct {
+	c    theme.Colorizer
+	used bool
+}
+
+func (p *painter) indicator(seq string) string {
+	out := ""
+	if !p.used {
+		p.used = true
+		out = p.reset()
+	}
+	return out + "\x1b[" + seq + "m"
+}
+
+func (p *painter) reset() string {
+	seq, _ := p.c.Resolve("rs", "")
+	return "\x1b[" + seq + "m"
+}
+
+// normal starts an entry with the NORMAL color, if the theme has one
+func (p *painter) normal() string {
+	if seq, ok := p.c.Indicator("no"); ok {
+		return p.indicator(seq)
+	}
+	return ""
+}
+
+// name colors a file name with the sequence code resolves to
+func (p *painter) name(code, name, text string) string {
+	_, normal := p.c.Indicator("no")
+	seq, ok := p.c.Resolve(code, name)
+
+	var out strings.Builder
+	if ok {
+		if normal {
+			// Clear the NORMAL color so its attributes don't combine with the entry's
+			out.WriteString(p.indicator(""))
+		}
+		out.WriteString(p.indicator(seq))
+	}
+	out.WriteString(text)
+	if ok || normal {
+		out.WriteString(p.reset())
+	}
+	return out.String()
+}
+
+// renderColumns lays entries out top to bottom, then left to right, using as many
+// columns as fit within width. Columns are sized exactly like GNU ls sizes them.
+func (p *painter) renderColumns(entries []Entry, opts Options) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	widths := make([]int, len(entries))
+	for i, entry := range entries {
+		widths[i] = lipgloss.Width(entry.Name)
+	}
+
+	rows, colWidths := len(entries), []int{0}
+	if opts.Width > 0 {
+		// ls won't go below 3 cells per column, counting the two spaces between them
+		for cols := min(opts.Width/3, len(entries)); cols > 1; cols-- {
+			fitRows := (len(entries) + cols - 1) / cols
+			fitWidths := make([]int, cols)
+			total := 0
+			for i, w := range widths {
+				col := i / fitRows
+				if col != cols-1 {
+					w += 2
+				}
+				if w > fitWidths[col] {
+					total += w - fitWidths[col]
+					fitWidths[col] = w
+				}
+			}
+			if total < opts.Width {
+				rows, colWidths = fitRows, fitWidths
+				break
+			}
+		}
+	}
+
+	var out strings.Builder
+	for row := 0; row < rows; row++ {
+		for col := 0; col < len(colWidths); col++ {
+			i := col*rows + row
+			if i >= len(entries) {
+				break
+			}
+			out.WriteString(p.normal() + p.name(entries[i].Code(p.c), entries[i].Name, entries[i].Name))
+			if i+rows >= len(entries) {
+				break
+			}
+			// ls pads with spaces rather than tabs whenever it's coloring its output
+			out.WriteString(strings.Repeat(" ", colWidths[col]-widths[i]))
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// renderLong writes one entry per line with the same columns as `ls -l`
+func (p *painter) renderLong(entries []Entry) string {
+	rows := make([][]string, len(entries))
+	colWidths := make([]int, 5)
+	var blocks int64
+
+	// Devices show their major and minor numbers in place of a size, each aligned on their own
+	var majorWidth, minorWidth int
+	for _, entry := range entries {
+		if stat, ok := statOf(entry.Info); ok && entry.Info.Mode()&fs.ModeDevice != 0 {
+			majorWidth = max(majorWidth, len(strconv.FormatUint(stat.major, 10)))
+			minorWidth = max(minorWidth, len(strconv.FormatUint(stat.minor, 10)))
+		}
+	}
+
+	for i, entry := range entries {
+		var nlink uint64 = 1
+		owner, group := "?", "?"
+		size := strconv.FormatInt(entry.Info.Size(), 10)
+		if stat, ok := statOf(entry.Info); ok {
+			nlink = stat.nlink
+			owner = lookupUser(stat.uid)
+			group = lookupGroup(stat.gid)
+			blocks += stat.blocks
+			if entry.Info.Mode()&fs.ModeDevice != 0 {
+				size = fmt.Sprintf("%*v, %*v", majorWidth, stat.major, minorWidth, stat.minor)
+			}
+		}
+
+		rows[i] = []string{
+			modeString(entry.Info.Mode()),
+			strconv.FormatUint(nlink, 10),
+			owner,
+			group,
+			size,
+		}
+		for col, field := range rows[i] {
+			colWidths[col] = max(colWidths[col], len(field))
+		}
+	}
+
+	var out strings.Builder
+	// Stat_t counts 512 byte blocks, while ls reports 1K blocks
+	fmt.Fprintf(&out, "total %v\n", (blocks+1)/2)
+
+	for i, entry := range entries {
+		row := rows[i]
+		fmt.Fprintf(&out, "%v%v %*v %-*v %-*v %*v %v %v",
+			p.normal(),
+			row[0],
+			colWidths[1], row[1],
+			colWidths[2], row[2],
+			colWidths[3], row[3],
+			colWidths[4], row[4],
+			modTime(entry.Info.ModTime()),
+			p.name(entry.Code(p.c), entry.Name, entry.Name))
+
+		if entry.Info.Mode()&fs.ModeSymlink != 0 {
+			out.WriteString(" -> ")
+			// ls only looks at what links point to when the theme colors missing targets
+			_, orphan := p.c.Indicator("or")
+			_, missing := p.c.Indicator("mi")
+			if orphan || missing {
+				out.WriteString(p.name(entry.TargetCode(p.c), filepath.Base(entry.Target), entry.Target))
+			} else {
+				// An empty code never resolves to a color, but still ends any NORMAL color
+				out.WriteString(p.name("", "", entry.Target))
+			}
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// modeString formats a file mode the way ls does, including the setuid, setgid and sticky bits
+func modeString(mode fs.FileMode) string {
+	out := []byte("-rwxrwxrwx")
+
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		out[0] = 'l'
+	case mode.IsDir():
+		out[0] = 'd'
+	case mode&fs.ModeNamedPipe != 0:
+		out[0] = 'p'
+	case mode&fs.ModeSocket != 0:
+		out[0] = 's'
+	case mode&fs.ModeCharDevice != 0:
+		out[0] = 'c'
+	case mode&fs.ModeDevice != 0:
+		out[0] = 'b'
+	}
+
+	perm := mode.Perm()
+	for i := 0; i < 9; i++ {
+		if perm&(1<<(8-i)) == 0 {
+			out[i+1] = '-'
+		}
+	}
+
+	special := func(set bool, i int, lower byte) {
+		if !set {
+			return
+		}
+		if out[i] == '-' {
+			out[i] = lower - 'a' + 'A'
+		} else {
+			out[i] = lower
+		}
+	}
+	special(mode&fs.ModeSetuid != 0, 3, 's')
+	special(mode&fs.ModeSetgid != 0, 6, 's')
+	special(mode&fs.ModeSticky != 0, 9, 't')
+
+	return string(out)
+}
+
+// modTime formats a modification time the way ls does, swapping the time of day
+// for the year once it's more than six months away
+func modTime(t time.Time) string {
+	if since := time.Since(t); since > 6*30*24*time.Hour || since < -time.Hour {
+		return t.Format("Jan _2  2006")
+	}
+	return t.Format("Jan _2 15:04")
+}
+
+var userNames = map[string]string{}
+var groupNames = map[string]string{}
+
+func lookupUser(uid string) string {
+	if name, ok := userNames[uid]; ok {
+		return name
+	}
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	userNames[uid] = name
+	return name
+}
+
+func lookupGroup(gid string) string {
+	if name, ok := groupNames[gid]; ok {
+		return name
+	}
+	name := gid
+	if g, err := user.LookupGroupId(gid); err == nil {
+		name = g.Name
+	}
+	groupNames[gid] = name
+	return name
+}
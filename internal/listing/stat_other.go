@@ -0,0 +1,11 @@
+//go:build !unix
+
+package listing
+
+import "io/fs"
+
+// statOf reads the details `ls -l` shows that fs.FileInfo leaves out, which other systems don't
+// have, so listings fall back to a single link and an unknown owner
+func statOf(info fs.FileInfo) (fileStat, bool) {
+	return fileStat{}, false
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package listing
+
+import (
+	"io/fs"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// statOf reads the details `ls -l` shows that fs.FileInfo leaves out
+func statOf(info fs.FileInfo) (fileStat, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileStat{}, false
+	}
+
+	return fileStat{
+		nlink:  uint64(stat.Nlink),
+		uid:    strconv.FormatUint(uint64(stat.Uid), 10),
+		gid:    strconv.FormatUint(uint64(stat.Gid), 10),
+		blocks: int64(stat.Blocks),
+		major:  uint64(unix.Major(uint64(stat.Rdev))),
+		minor:  uint64(unix.Minor(uint64(stat.Rdev))),
+	}, true
+}
@@ -0,0 +1,109 @@
+package listing
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// TreeOptions control how a tree is rendered
+type TreeOptions struct {
+	Depth int  // How many levels below the root to descend. Unlimited when < 1
+	All   bool // Include hidden entries
+	ASCII bool // Draw connectors with plain ASCII instead of box drawing characters
+}
+
+// connectors are the pieces a tree's branches are drawn with
+type connectors struct {
+	branch, last, pipe, space string
+}
+
+var unicodeConnectors = connectors{"├── ", "└── ", "│   ", "    "}
+var asciiConnectors = connectors{"|-- ", "`-- ", "|   ", "    "}
+
+// WriteTree draws root and everything below it into w, colored with c, followed by a count of
+// the directories and files shown, the same as `tree`. Symlinked directories aren't followed.
+func WriteTree(w io.Writer, root string, c theme.Colorizer, opts TreeOptions) error {
+	rootEntry, err := Stat(root)
+	if err != nil {
+		return err
+	}
+
+	lines := unicodeConnectors
+	if opts.ASCII {
+		lines = asciiConnectors
+	}
+
+	t := treeWriter{w: w, c: c, opts: opts, lines: lines}
+	fmt.Fprintln(w, c.Paint(rootEntry.Code(c), rootEntry.Name, root))
+	if err := t.write(root, "", 1); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n%v, %v\n", plural(t.dirs, "directory", "directories"), plural(t.files, "file", "files"))
+	return nil
+}
+
+type treeWriter struct {
+	w     io.Writer
+	c     theme.Colorizer
+	opts  TreeOptions
+	lines connectors
+
+	dirs, files int
+}
+
+func (t *treeWriter) write(dir, prefix string, depth int) error {
+	entries, err := Scan(dir, t.opts.All)
+	if err != nil {
+		return err
+	}
+
+	// Scan includes . and .. with hidden entries, which a tree has no use for
+	visible := entries[:0]
+	for _, entry := range entries {
+		if entry.Name != "." && entry.Name != ".." {
+			visible = append(visible, entry)
+		}
+	}
+
+	for i, entry := range visible {
+		connector, indent := t.lines.branch, t.lines.pipe
+		if i == len(visible)-1 {
+			connector, indent = t.lines.last, t.lines.space
+		}
+
+		line := t.c.Paint(entry.Code(t.c), entry.Name, entry.Name)
+		if entry.Target != "" {
+			line += " -> " + t.c.Paint(entry.TargetCode(t.c), filepath.Base(entry.Target), entry.Target)
+		}
+		fmt.Fprintln(t.w, prefix+connector+line)
+
+		// Like `tree`, symlinks are counted as whatever they point to, but never descended into
+		if entry.TargetInfo != nil && entry.TargetInfo.IsDir() {
+			t.dirs++
+			continue
+		}
+		if !entry.Info.IsDir() {
+			t.files++
+			continue
+		}
+		t.dirs++
+		if t.opts.Depth < 1 || depth < t.opts.Depth {
+			if err := t.write(filepath.Join(dir, entry.Name), prefix+indent, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%v %v", n, one)
+	}
+	return fmt.Sprintf("%v %v", n, many)
+}
@@ -0,0 +1,50 @@
+package listing
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+func TestWriteTreeSummary(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"docs", "src/inner"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"README.md", "src/main.go"} {
+		if err := os.WriteFile(filepath.Join(root, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{"docs-link": "docs", "readme-link": "README.md", "orphan": "missing"} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		depth int
+		want  string
+	}{
+		// The symlinked directory counts as one, but its contents aren't counted again
+		{0, "4 directories, 4 files"},
+		{1, "3 directories, 3 files"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := WriteTree(&out, root, theme.ParseColorizer(""), TreeOptions{Depth: test.depth}); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if got := lines[len(lines)-1]; got != test.want {
+			t.Errorf("depth %v: summary = %q, want %q", test.depth, got, test.want)
+		}
+	}
+}
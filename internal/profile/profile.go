@@ -0,0 +1,77 @@
+// Package profile picks the terminal color profile stylish encodes colors for
+package profile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// The names of the color profiles a theme can be encoded for
+const (
+	TrueColor = "truecolor"
+	ANSI256   = "256"
+	ANSI      = "16"
+	None      = "none"
+
+	// Auto asks Detect to work out the profile from the environment instead of naming one
+	Auto = "auto"
+)
+
+// Names lists every value accepted by Parse
+var Names = []string{TrueColor, ANSI256, ANSI, None, Auto}
+
+// profiles maps each name to the termenv profile it stands for
+var profiles = map[string]termenv.Profile{
+	TrueColor: termenv.TrueColor,
+	ANSI256:   termenv.ANSI256,
+	ANSI:      termenv.ANSI,
+	None:      termenv.Ascii,
+}
+
+// Parse looks up the named profile, detecting it from the environment for `auto`
+func Parse(name string) (termenv.Profile, error) {
+	name = strings.ToLower(name)
+	if name == Auto {
+		return Detect(), nil
+	}
+	if profile, ok := profiles[name]; ok {
+		return profile, nil
+	}
+
+	return termenv.TrueColor, fmt.Errorf("unknown color profile %q, expected one of: %v", name, strings.Join(Names, ", "))
+}
+
+// Name is the name Parse accepts for the given profile
+func Name(profile termenv.Profile) string {
+	for name, p := range profiles {
+		if p == profile {
+			return name
+		}
+	}
+	return TrueColor
+}
+
+// Detect works out the color profile from $COLORTERM and $TERM. Unlike termenv, it doesn't care
+// whether stdout is a terminal, since `apply` is usually run inside `eval`.
+func Detect() termenv.Profile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return termenv.TrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "" || term == "dumb":
+		return termenv.Ascii
+	case strings.HasSuffix(term, "-direct"), strings.HasPrefix(term, "xterm-kitty"),
+		strings.HasPrefix(term, "alacritty"), strings.HasPrefix(term, "wezterm"), strings.HasPrefix(term, "xterm-ghostty"):
+		return termenv.TrueColor
+	case strings.Contains(term, "256color"):
+		return termenv.ANSI256
+	}
+	// Anything else, like the Linux console, screen or plain xterm, is assumed to only have the basic 16
+	return termenv.ANSI
+}
@@ -0,0 +1,77 @@
+// Package shell formats environment variable exports for the shells stylish supports
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Shell is a family of shells that share the same export syntax
+type Shell string
+
+const (
+	Posix Shell = "bash" // bash, zsh, sh, and friends
+	Fish  Shell = "fish"
+	Nu    Shell = "nu"
+	Pwsh  Shell = "pwsh"
+	Csh   Shell = "csh" // csh and tcsh
+	Raw   Shell = "raw" // Just the value, no export syntax
+)
+
+// Names lists every name accepted by Parse
+var Names = []string{"bash", "zsh", "sh", "fish", "nu", "pwsh", "csh", "tcsh", "raw"}
+
+// Parse will convert a shell's name into its Shell family
+func Parse(name string) (Shell, error) {
+	switch strings.ToLower(name) {
+	case "bash", "zsh", "sh", "dash", "ksh", "mksh", "posix":
+		return Posix, nil
+	case "fish":
+		return Fish, nil
+	case "nu", "nushell":
+		return Nu, nil
+	case "pwsh", "powershell":
+		return Pwsh, nil
+	case "csh", "tcsh":
+		return Csh, nil
+	case "raw":
+		return Raw, nil
+	}
+
+	return "", fmt.Errorf("unknown shell %q, expected one of: %v", name, strings.Join(Names, ", "))
+}
+
+// Detect will determine the user's shell from $SHELL, falling back to Posix
+func Detect() Shell {
+	sh, err := Parse(strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe"))
+	if err != nil {
+		return Posix
+	}
+	return sh
+}
+
+// Export returns the statement that sets the environment variable `name` to `value` in the given shell
+func Export(sh Shell, name, value string) string {
+	switch sh {
+	case Fish:
+		value = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+		return fmt.Sprintf("set -gx %v '%v';\n", name, value)
+	case Nu:
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+		return fmt.Sprintf("$env.%v = \"%v\"\n", name, value)
+	case Pwsh:
+		// PowerShell treats the typographic single quotes as quotes too
+		value = strings.NewReplacer(`'`, `''`, "‘", "‘‘", "’", "’’", "‚", "‚‚", "‛", "‛‛").Replace(value)
+		return fmt.Sprintf("$env:%v = '%v'\n", name, value)
+	case Csh:
+		value = strings.NewReplacer(`'`, `'\''`, `!`, `\!`).Replace(value)
+		return fmt.Sprintf("setenv %v '%v'\n", name, value)
+	case Raw:
+		return value + "\n"
+	default:
+		value = strings.ReplaceAll(value, `'`, `'\''`)
+		return fmt.Sprintf("%v='%v';\nexport %v\n", name, value, name)
+	}
+}
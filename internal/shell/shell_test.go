@@ -0,0 +1,76 @@
+package shell
+
+import "testing"
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		sh    Shell
+		value string
+		want  string
+	}{
+		{Posix, "di=01;34:", "LS_COLORS='di=01;34:';\nexport LS_COLORS\n"},
+		{Posix, "*it's=1:", "LS_COLORS='*it'\\''s=1:';\nexport LS_COLORS\n"},
+		{Fish, "di=01;34:", "set -gx LS_COLORS 'di=01;34:';\n"},
+		{Fish, `*it's\=1:`, "set -gx LS_COLORS '*it\\'s\\\\=1:';\n"},
+		{Nu, "di=01;34:", "$env.LS_COLORS = \"di=01;34:\"\n"},
+		{Nu, `*"a"\=1:`, "$env.LS_COLORS = \"*\\\"a\\\"\\\\=1:\"\n"},
+		{Pwsh, "di=01;34:", "$env:LS_COLORS = 'di=01;34:'\n"},
+		{Pwsh, "*it's=1:*it’s=1:", "$env:LS_COLORS = '*it''s=1:*it’’s=1:'\n"},
+		{Csh, "di=01;34:", "setenv LS_COLORS 'di=01;34:'\n"},
+		{Csh, "*it's!=1:", "setenv LS_COLORS '*it'\\''s\\!=1:'\n"},
+		{Raw, "*it's=1:", "*it's=1:\n"},
+	}
+
+	for _, test := range tests {
+		if got := Export(test.sh, "LS_COLORS", test.value); got != test.want {
+			t.Errorf("Export(%v, %q) = %q, want %q", test.sh, test.value, got, test.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Shell
+	}{
+		{"bash", Posix},
+		{"ZSH", Posix},
+		{"dash", Posix},
+		{"fish", Fish},
+		{"nushell", Nu},
+		{"powershell", Pwsh},
+		{"tcsh", Csh},
+		{"raw", Raw},
+	}
+
+	for _, test := range tests {
+		got, err := Parse(test.name)
+		if err != nil || got != test.want {
+			t.Errorf("Parse(%q) = %v, %v, want %v", test.name, got, err, test.want)
+		}
+	}
+
+	if _, err := Parse("cmd.exe"); err == nil {
+		t.Error(`Parse("cmd.exe") didn't return an error`)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env  string
+		want Shell
+	}{
+		{"/usr/bin/fish", Fish},
+		{"/bin/tcsh", Csh},
+		{"pwsh.exe", Pwsh},
+		{"", Posix},
+		{"/bin/unknown", Posix},
+	}
+
+	for _, test := range tests {
+		t.Setenv("SHELL", test.env)
+		if got := Detect(); got != test.want {
+			t.Errorf("Detect() with SHELL=%q = %v, want %v", test.env, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// pickerRow is one of the rows of the color picker that can be focused
+type pickerRow int
+
+const (
+	rowHex pickerRow = iota
+	rowHue
+	rowSat
+	rowLight
+	rowRed
+	rowGreen
+	rowBlue
+	rowPalette
+	rowRecent
+	rowTheme
+	pickerRowCount
+)
+
+const sliderWidth = 20
+const maxRecentColors = 10
+
+// ColorPicker picks a color with a hex input, hue/saturation/lightness and RGB sliders,
+// or from rows of swatches. Every way of picking writes back to the hex input.
+// Picking from the palette row refers to the palette entry instead of copying its hex code.
+type ColorPicker struct {
+	Input textinput.Model
+
+	// Recent and Theme are the swatch rows, as hex codes without the leading '#'
+	Recent []string
+	Theme  []string
+	// Palette is the theme's palette, shown as its own swatch row
+	Palette theme.Palette
+
+	ref     string // Name of the picked palette entry, until the color is changed any other way
+	h, s, l float64
+	row     pickerRow
+	swatch  int // Selected swatch of the focused row, -1 until one's been picked
+	focused bool
+}
+
+func NewColorPicker() ColorPicker {
+	input := textinput.New()
+	input.CharLimit = 6
+	input.Prompt = "#"
+	input.Validate = theme.ValidHexCode
+
+	return ColorPicker{Input: input, l: 0.5}
+}
+
+// Value is the picked color as it should be saved to a style: either a hex code without the
+// leading '#', or a `$name` palette reference. Empty if nothing's been picked.
+func (p ColorPicker) Value() string {
+	if p.ref != "" {
+		return theme.PaletteRefPrefix + p.ref
+	}
+	return p.Input.Value()
+}
+
+// Hex is the picked color as a hex code, even when it comes from the palette
+func (p ColorPicker) Hex() string {
+	return p.Input.Value()
+}
+
+// SetValue sets the picked color and moves the sliders to match it.
+// Palette references that can't be resolved leave the hex input empty.
+func (p *ColorPicker) SetValue(color string) {
+	p.ref = ""
+	if theme.IsPaletteRef(color) {
+		name := strings.TrimPrefix(color, theme.PaletteRefPrefix)
+		if hex, ok := p.Palette[name]; ok {
+			p.ref = name
+			color = hex
+		} else {
+			color = ""
+		}
+	}
+	p.Input.SetValue(color)
+	p.syncFromInput()
+}
+
+func (p *ColorPicker) Focus() tea.Cmd {
+	p.focused = true
+	p.row = rowHex
+	p.swatch = -1
+	return p.Input.Focus()
+}
+
+func (p *ColorPicker) Blur() {
+	p.focused = false
+	p.Input.Blur()
+}
+
+// Err is the validation error of the hex input, if there is one
+func (p ColorPicker) Err() error {
+	return p.Input.Err
+}
+
+// AddRecent moves hex to the front of the recent colors, dropping the oldest once the row is full
+func AddRecent(recent []string, hex string) []string {
+	if hex == "" {
+		return recent
+	}
+
+	out := []string{hex}
+	for _, color := range recent {
+		if !strings.EqualFold(color, hex) && len(out) < maxRecentColors {
+			out = append(out, color)
+		}
+	}
+	return out
+}
+
+func (p ColorPicker) Update(msg tea.Msg) (ColorPicker, tea.Cmd) {
+	if !p.focused {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "shift+tab":
+		p.focusRow((p.row + pickerRowCount - 1) % pickerRowCount)
+		return p, nil
+	case "down", "tab":
+		p.focusRow((p.row + 1) % pickerRowCount)
+		return p, nil
+	case "left", "right", "shift+left", "shift+right":
+		if p.row == rowHex {
+			break
+		}
+		step := 1
+		if strings.HasPrefix(keyMsg.String(), "shift+") {
+			step = 10
+		}
+		if strings.HasSuffix(keyMsg.String(), "left") {
+			step = -step
+		}
+		p.adjust(step)
+		return p, nil
+	}
+
+	if p.row != rowHex {
+		return p, nil
+	}
+
+	var cmd tea.Cmd
+	before := p.Input.Value()
+	p.Input, cmd = p.Input.Update(msg)
+	if p.Input.Value() != before {
+		p.ref = ""
+	}
+	p.syncFromInput()
+	return p, cmd
+}
+
+// focusRow moves focus to row, handing the keyboard to the hex input only while it's focused
+func (p *ColorPicker) focusRow(row pickerRow) {
+	p.row = row
+	p.swatch = -1
+	if row == rowHex {
+		p.Input.Focus()
+	} else {
+		p.Input.Blur()
+	}
+}
+
+// adjust moves the focused slider or swatch selection by step
+func (p *ColorPicker) adjust(step int) {
+	r, g, b := p.color().RGB255()
+
+	switch p.row {
+	case rowHue:
+		p.h = math.Mod(p.h+float64(step)*3+360, 360)
+	case rowSat:
+		p.s = clamp(p.s+float64(step)/100, 0, 1)
+	case rowLight:
+		p.l = clamp(p.l+float64(step)/100, 0, 1)
+	case rowRed, rowGreen, rowBlue:
+		channels := []*uint8{&r, &g, &b}
+		channel := channels[p.row-rowRed]
+		*channel = uint8(clamp(float64(*channel)+float64(step)*5, 0, 255))
+		p.h, p.s, p.l = colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}.Hsl()
+	case rowPalette, rowRecent, rowTheme:
+		swatches := p.swatches()
+		if len(swatches) == 0 {
+			return
+		}
+		if p.swatch < 0 && step < 0 {
+			p.swatch = len(swatches) - 1
+		} else if p.swatch < 0 {
+			p.swatch = 0
+		} else {
+			p.swatch = (p.swatch + step%len(swatches) + len(swatches)) % len(swatches)
+		}
+		p.Input.SetValue(swatches[p.swatch])
+		p.syncFromInput()
+		p.ref = ""
+		if p.row == rowPalette {
+			p.ref = p.Palette.Names()[p.swatch]
+		}
+		return
+	}
+
+	p.ref = ""
+	p.Input.SetValue(strings.ToUpper(strings.TrimPrefix(p.color().Hex(), "#")))
+}
+
+// syncFromInput moves the sliders to the hex input's color, if it's valid
+func (p *ColorPicker) syncFromInput() {
+	if theme.ValidHexCode(p.Input.Value()) != nil {
+		return
+	}
+	color, err := colorful.Hex("#" + p.Input.Value())
+	if err != nil {
+		return
+	}
+	h, s, l := color.Hsl()
+	// Grays have no hue, so keep whichever one the slider was already on
+	if s > 0 {
+		p.h = h
+	}
+	p.s, p.l = s, l
+}
+
+func (p ColorPicker) color() colorful.Color {
+	return colorful.Hsl(p.h, p.s, p.l).Clamped()
+}
+
+func (p ColorPicker) swatches() []string {
+	switch p.row {
+	case rowPalette:
+		return p.paletteHexes()
+	case rowRecent:
+		return p.Recent
+	}
+	return p.Theme
+}
+
+// paletteHexes lists the palette's colors in the same order as its names
+func (p ColorPicker) paletteHexes() []string {
+	var hexes []string
+	for _, name := range p.Palette.Names() {
+		hexes = append(hexes, p.Palette[name])
+	}
+	return hexes
+}
+
+func (p ColorPicker) View() string {
+	r, g, b := p.color().RGB255()
+
+	paletteLabel := p.label(rowPalette, "Palette")
+	if p.ref != "" {
+		paletteLabel += SubtitleStyle.Render(theme.PaletteRefPrefix + p.ref)
+	}
+
+	rows := []string{
+		p.label(rowHex, "Hex") + p.Input.View(),
+		"",
+		p.slider(rowHue, "H", p.h/360, fmt.Sprintf("%3.0f°", p.h), func(t float64) colorful.Color { return colorful.Hsl(t*360, p.s, p.l) }),
+		p.slider(rowSat, "S", p.s, fmt.Sprintf("%3.0f%%", p.s*100), func(t float64) colorful.Color { return colorful.Hsl(p.h, t, p.l) }),
+		p.slider(rowLight, "L", p.l, fmt.Sprintf("%3.0f%%", p.l*100), func(t float64) colorful.Color { return colorful.Hsl(p.h, p.s, t) }),
+		"",
+		p.slider(rowRed, "R", float64(r)/255, fmt.Sprintf("%4v", r), func(t float64) colorful.Color {
+			return colorful.Color{R: t, G: float64(g) / 255, B: float64(b) / 255}
+		}),
+		p.slider(rowGreen, "G", float64(g)/255, fmt.Sprintf("%4v", g), func(t float64) colorful.Color {
+			return colorful.Color{R: float64(r) / 255, G: t, B: float64(b) / 255}
+		}),
+		p.slider(rowBlue, "B", float64(b)/255, fmt.Sprintf("%4v", b), func(t float64) colorful.Color {
+			return colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: t}
+		}),
+		"",
+		paletteLabel,
+		p.swatchRow(rowPalette, p.paletteHexes()),
+		p.label(rowRecent, "Recent"),
+		p.swatchRow(rowRecent, p.Recent),
+		p.label(rowTheme, "In Theme"),
+		p.swatchRow(rowTheme, p.Theme),
+	}
+
+	return lipgloss.NewStyle().PaddingLeft(2).Render(strings.Join(rows, "\n"))
+}
+
+// label renders a row's name, highlighted while the row is focused
+func (p ColorPicker) label(row pickerRow, name string) string {
+	if p.row == row {
+		return ActiveAttrStyle.Render("> "+name) + " "
+	}
+	return InactiveAttrStyle.Render("  "+name) + " "
+}
+
+// slider draws a gradient of every color the slider can reach, with a marker at its current position
+func (p ColorPicker) slider(row pickerRow, name string, pos float64, value string, at func(float64) colorful.Color) string {
+	marker := int(math.Round(pos * (sliderWidth - 1)))
+
+	var bar strings.Builder
+	for i := 0; i < sliderWidth; i++ {
+		cell := at(float64(i) / (sliderWidth - 1)).Clamped()
+		style := lipgloss.NewStyle().Background(lipgloss.Color(cell.Hex()))
+		if i == marker {
+			_, _, l := cell.Hsl()
+			contrast := "#000000"
+			if l < 0.5 {
+				contrast = "#FFFFFF"
+			}
+			bar.WriteString(style.Foreground(lipgloss.Color(contrast)).Render("┃"))
+		} else {
+			bar.WriteString(style.Render(" "))
+		}
+	}
+
+	return p.label(row, name) + bar.String() + " " + value
+}
+
+// swatchRow draws a row of colors, marking the selected one while the row is focused.
+// Long rows scroll to keep the selection in view.
+func (p ColorPicker) swatchRow(row pickerRow, colors []string) string {
+	if len(colors) == 0 {
+		return "    " + HelpDescStyle.Render("None yet")
+	}
+
+	const visible = 7
+	start := 0
+	if p.row == row && p.swatch >= visible {
+		start = p.swatch - visible + 1
+	}
+
+	var out strings.Builder
+	out.WriteString("   ")
+	for i := start; i < len(colors) && i < start+visible; i++ {
+		swatch := lipgloss.NewStyle().Foreground(lipgloss.Color("#" + colors[i])).Render("██")
+		if p.row == row && i == p.swatch {
+			out.WriteString(ActiveAttrStyle.Render("[") + swatch + ActiveAttrStyle.Render("]"))
+		} else {
+			out.WriteString(" " + swatch + " ")
+		}
+	}
+	return out.String()
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
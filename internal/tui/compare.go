@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// compareRows is how many styles the matcher comparison shows at once
+const compareRows = 15
+
+// compareColor is the color of a style the comparison screen matches: its foreground, or its
+// background for styles that only set one of those
+type compareColor struct {
+	name string
+	hex  string
+}
+
+// updateCompare handles keys while the matcher comparison is open. 1-3 pick which matcher
+// the editor previews with.
+func (m ThemeModel) updateCompare(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	colors := m.compareColors()
+	switch keyMsg.String() {
+	case "k", "up":
+		m.compareOffset = max(m.compareOffset-1, 0)
+	case "j", "down":
+		m.compareOffset = min(m.compareOffset+1, max(len(colors)-compareRows, 0))
+	case "1", "2", "3":
+		m.matcher = theme.Matchers[int(keyMsg.String()[0]-'1')]
+		m.refreshStyleItems()
+	case "C", "esc":
+		m.compareActive = false
+	}
+	return m, nil
+}
+
+// compareColors lists the colors of every style that sets one, as they're currently shown
+func (m ThemeModel) compareColors() []compareColor {
+	var colors []compareColor
+	for _, item := range m.StyleList.Items() {
+		style := item.(styleItem).ForBackground(m.variant)
+		if hex := style.ResolvedFore(); theme.ValidHexCode(hex) == nil {
+			colors = append(colors, compareColor{style.Name, hex})
+		} else if hex := style.ResolvedBack(); theme.ValidHexCode(hex) == nil {
+			colors = append(colors, compareColor{style.Name, hex})
+		}
+	}
+	return colors
+}
+
+func (m ThemeModel) getCompareModel() string {
+	colors := m.compareColors()
+
+	// The 256 color each matcher picks for every style, and which different colors end up sharing one
+	picks := make([][]termenv.ANSI256Color, len(theme.Matchers))
+	shared := make([]int, len(theme.Matchers))
+	for i, matcher := range theme.Matchers {
+		hexes := make(map[termenv.ANSI256Color]map[string]bool)
+		for _, color := range colors {
+			pick := matcher.Nearest256(color.hex)
+			picks[i] = append(picks[i], pick)
+			if hexes[pick] == nil {
+				hexes[pick] = make(map[string]bool)
+			}
+			hexes[pick][strings.ToUpper(color.hex)] = true
+		}
+		for _, pick := range picks[i] {
+			if len(hexes[pick]) > 1 {
+				shared[i]++
+			}
+		}
+	}
+
+	nameColumn := func(name string) string { return fmt.Sprintf("%-9.9s", name) }
+	column := lipgloss.NewStyle().Width(7)
+
+	var body strings.Builder
+	body.WriteString(nameColumn("") + "    ")
+	for _, matcher := range theme.Matchers {
+		label := strings.TrimSuffix(string(matcher), "2000")
+		if matcher == m.selectedMatcher() {
+			body.WriteString(column.Render(ActiveAttrStyle.Render(label)))
+		} else {
+			body.WriteString(column.Render(InactiveAttrStyle.Render(label)))
+		}
+	}
+	body.WriteString("\n")
+
+	if len(colors) == 0 {
+		body.WriteString("\n" + HelpDescStyle.Render("No styles with colors yet") + "\n")
+	}
+	end := min(m.compareOffset+compareRows, len(colors))
+	for row := m.compareOffset; row < end; row++ {
+		color := colors[row]
+		swatch := lipgloss.NewStyle().Foreground(lipgloss.Color("#" + color.hex)).Render("██")
+		body.WriteString(nameColumn(color.name) + " " + swatch + " ")
+		for i := range theme.Matchers {
+			pick := picks[i][row]
+			pickSwatch := lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(pick)).Render("█")
+			body.WriteString(column.Render(fmt.Sprintf("%v%4d", pickSwatch, int(pick))))
+		}
+		body.WriteString("\n")
+	}
+
+	// Shared counts the styles matched to the same 256 color as a style with a different color
+	body.WriteString("\n" + HelpDescStyle.Render(nameColumn("Shared")) + "    ")
+	for _, count := range shared {
+		body.WriteString(column.Render(fmt.Sprintf("%5d", count)))
+	}
+
+	keyStyle := m.help.Styles.FullKey
+	descStyle := m.help.Styles.FullDesc
+	footer := CenterHorz(keyStyle.Render("1-3")+descStyle.Render(" Preview with one  ")+
+		keyStyle.Render("j/k")+descStyle.Render(" Scroll")) + "\n" +
+		CenterHorz(keyStyle.Render("C/esc")+descStyle.Render(" Close"))
+
+	header := CenterHorz(TitleStyle.Render("256 Color Matchers") + "\n" + SubtitleStyle.Render("Theme: "+m.Theme.Name))
+	return RenderModel(fmt.Sprintf("%v\n\n%v", header, lipgloss.NewStyle().PaddingLeft(2).Render(body.String())), footer, m.err)
+}
+
+// selectedMatcher is the matcher the editor previews with, which is rgb until another is picked
+func (m ThemeModel) selectedMatcher() theme.Matcher {
+	if m.matcher == "" {
+		return theme.MatchRGB
+	}
+	return m.matcher
+}
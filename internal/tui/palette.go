@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// paletteMode is what the palette screen is currently doing
+type paletteMode int
+
+const (
+	paletteBrowsing paletteMode = iota
+	paletteNaming               // Typing the name of a new entry
+	paletteRenaming             // Typing a new name for the selected entry
+	paletteColoring             // Picking the color of paletteName
+	paletteDeleting             // Confirming the removal of the selected entry
+)
+
+// updatePalette handles every message while the palette screen is open
+func (m ThemeModel) updatePalette(msg tea.Msg) (tea.Model, tea.Cmd) {
+	names := m.Theme.Palette.Names()
+	var selected string
+	if len(names) > 0 {
+		m.paletteCursor = min(m.paletteCursor, len(names)-1)
+		selected = names[m.paletteCursor]
+	}
+
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	if isKey {
+		m.err = nil
+	}
+
+	switch m.paletteMode {
+	case paletteBrowsing:
+		if !isKey {
+			return m, nil
+		}
+		switch keyMsg.String() {
+		case "k", "up":
+			if len(names) > 0 {
+				m.paletteCursor = (m.paletteCursor + len(names) - 1) % len(names)
+			}
+		case "j", "down":
+			if len(names) > 0 {
+				m.paletteCursor = (m.paletteCursor + 1) % len(names)
+			}
+		case "n": // New entry
+			m.paletteMode = paletteNaming
+			m.NameInput.SetValue("")
+			return m, m.NameInput.Focus()
+		case "r": // Rename entry
+			if selected != "" {
+				m.paletteMode = paletteRenaming
+				m.NameInput.SetValue(selected)
+				return m, m.NameInput.Focus()
+			}
+		case "enter", "f": // Recolor entry
+			if selected != "" {
+				return m, m.pickPaletteColor(selected)
+			}
+		case "d", "x": // Remove entry
+			if selected != "" {
+				m.paletteMode = paletteDeleting
+			}
+		case "esc", "ctrl+c", "P":
+			m.paletteActive = false
+		}
+		return m, nil
+
+	case paletteDeleting:
+		if isKey && keyMsg.String() == "y" {
+			m.err = m.editPaletteStyles(func(t *theme.Theme) error { return t.RemovePaletteColor(selected) })
+			m.paletteMode = paletteBrowsing
+		} else if isKey {
+			m.paletteMode = paletteBrowsing
+		}
+		return m, nil
+
+	case paletteNaming, paletteRenaming:
+		if isKey {
+			switch keyMsg.String() {
+			case "ctrl+s":
+				return m.savePaletteName(selected)
+			case "ctrl+c", "esc":
+				m.NameInput.Blur()
+				m.paletteMode = paletteBrowsing
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.NameInput, cmd = m.NameInput.Update(msg)
+		return m, cmd
+
+	case paletteColoring:
+		if isKey {
+			switch keyMsg.String() {
+			case "ctrl+s":
+				if err := m.Theme.SetPaletteColor(m.paletteName, m.ColorInput.Hex()); err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.relint()
+				m.ColorInput.Recent = AddRecent(m.ColorInput.Recent, m.ColorInput.Hex())
+				m.ColorInput.Blur()
+				m.paletteCursor = indexOf(m.Theme.Palette.Names(), m.paletteName)
+				m.paletteMode = paletteBrowsing
+				return m, nil
+			case "ctrl+c", "esc":
+				m.ColorInput.Blur()
+				m.paletteMode = paletteBrowsing
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.ColorInput, cmd = m.ColorInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// savePaletteName finishes typing a name. New entries move on to picking their color,
+// while renamed entries are rewritten in every style that refers to them.
+func (m ThemeModel) savePaletteName(selected string) (tea.Model, tea.Cmd) {
+	name := strings.TrimSpace(m.NameInput.Value())
+
+	if m.paletteMode == paletteRenaming {
+		m.err = m.editPaletteStyles(func(t *theme.Theme) error { return t.RenamePaletteColor(selected, name) })
+		if m.err == nil {
+			m.NameInput.Blur()
+			m.paletteCursor = indexOf(m.Theme.Palette.Names(), name)
+			m.paletteMode = paletteBrowsing
+		}
+		return m, nil
+	}
+
+	if err := theme.ValidPaletteName(name); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if _, exists := m.Theme.Palette[name]; exists {
+		m.err = fmt.Errorf("palette color %q already exists", name)
+		return m, nil
+	}
+
+	m.NameInput.Blur()
+	return m, m.pickPaletteColor(name)
+}
+
+// pickPaletteColor opens the color picker for the palette entry name
+func (m *ThemeModel) pickPaletteColor(name string) tea.Cmd {
+	m.paletteName = name
+	m.paletteMode = paletteColoring
+	m.ColorInput.Palette = m.Theme.Palette
+	m.ColorInput.Theme = m.themeColors()
+	m.ColorInput.SetValue(m.Theme.Palette[name])
+	return m.ColorInput.Focus()
+}
+
+// editPaletteStyles runs edit against the theme as it currently stands,
+// then copies any styles it rewrote back into the style list
+func (m *ThemeModel) editPaletteStyles(edit func(*theme.Theme) error) error {
+	current := m.currentTheme()
+	err := edit(&current)
+
+	for i, item := range m.StyleList.Items() {
+		*item.(styleItem).Style = current.Styles[i]
+	}
+	m.Theme.Palette = current.Palette
+	m.relint()
+
+	return err
+}
+
+func (m ThemeModel) getPaletteModel() string {
+	switch m.paletteMode {
+	case paletteColoring:
+		return m.colorPickerView("Palette Color: "+m.paletteName, m.getEditHelpTextNoClear())
+	case paletteNaming:
+		return RenderModel(fmt.Sprintf("%v\n\n%v\n",
+			CenterHorz(TitleStyle.Render("New Palette Color")), CenterHorz(m.NameInput.View())), m.getEditHelpTextNoClear(), m.err)
+	case paletteRenaming:
+		return RenderModel(fmt.Sprintf("%v\n\n%v\n",
+			CenterHorz(TitleStyle.Render("Rename Palette Color")), CenterHorz(m.NameInput.View())), m.getEditHelpTextNoClear(), m.err)
+	}
+
+	current := m.currentTheme()
+	names := current.Palette.Names()
+
+	var body strings.Builder
+	if len(names) == 0 {
+		body.WriteString(CenterHorz(HelpDescStyle.Render("No palette colors yet")))
+	}
+	for i, name := range names {
+		hex := current.Palette[name]
+		swatch := lipgloss.NewStyle().Foreground(lipgloss.Color("#" + hex)).Render("██")
+		// How many styles would be recolored by changing this entry
+		users := HelpDescStyle.Render(fmt.Sprintf("(%v)", len(current.PaletteUsers(name))))
+
+		label := InactiveAttrStyle.Render("  " + theme.PaletteRefPrefix + name)
+		if i == m.paletteCursor {
+			label = ActiveAttrStyle.Render("> " + theme.PaletteRefPrefix + name)
+		}
+		body.WriteString(fmt.Sprintf("%v %v #%v %v\n", swatch, lipgloss.NewStyle().Width(15).Render(label), hex, users))
+	}
+
+	if m.paletteMode == paletteDeleting {
+		body.WriteString("\n" + CenterHorz(WarningStyle.Render("Remove this color? (y/n)")) + "\n")
+		body.WriteString(CenterHorz(HelpDescStyle.Render("Its styles keep the color as hex")))
+	}
+
+	keyStyle := m.help.Styles.FullKey
+	descStyle := m.help.Styles.FullDesc
+	footer := CenterHorz(keyStyle.Render("n")+descStyle.Render(" New  ")+
+		keyStyle.Render("enter")+descStyle.Render(" Recolor  ")+
+		keyStyle.Render("r")+descStyle.Render(" Rename")) + "\n" +
+		CenterHorz(keyStyle.Render("d")+descStyle.Render(" Remove  ")+
+			keyStyle.Render("esc")+descStyle.Render(" Close"))
+
+	header := CenterHorz(TitleStyle.Render("Palette") + "\n" + SubtitleStyle.Render("Theme: "+m.Theme.Name))
+	return RenderModel(fmt.Sprintf("%v\n\n%v", header, lipgloss.NewStyle().PaddingLeft(2).Render(body.String())), footer, m.err)
+}
+
+func indexOf(items []string, item string) int {
+	for i, candidate := range items {
+		if candidate == item {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"go.dalton.dog/stylish/internal/listing"
+	"go.dalton.dog/stylish/theme"
+)
+
+const PreviewWidth = 40
+
+// loadPreview scans the directory shown in the preview pane. Entries are only scanned here,
+// while their colors are worked out on every render, so edits show up right away.
+func (m *ThemeModel) loadPreview() {
+	m.previewEntries, m.previewErr = nil, nil
+
+	dir, err := os.Getwd()
+	if m.previewExample {
+		dir = filepath.Join(m.Theme.Path, "example")
+	}
+	if err != nil {
+		m.previewErr = err
+		return
+	}
+
+	m.previewEntries, m.previewErr = listing.Scan(dir, false)
+}
+
+// previewTheme is the theme as it currently stands, shown with the simulated color vision deficiency
+// if there is one, and encoded for the editor's color profile and matcher
+func (m ThemeModel) previewTheme() theme.Theme {
+	edited := m.editedTheme()
+	if m.simulation != "" {
+		edited = edited.Simulate(m.simulation)
+	}
+	return edited.WithProfile(m.profile).WithMatcher(m.matcher)
+}
+
+// editedTheme is the theme as it currently stands, including a color or set of filetypes
+// that's still being typed in for the selected style
+func (m ThemeModel) editedTheme() theme.Theme {
+	current := m.currentTheme()
+	if m.paletteActive && m.paletteMode == paletteColoring && theme.ValidHexCode(m.ColorInput.Hex()) == nil {
+		// Recolor a copy, so the palette itself only changes once the color is saved
+		palette := make(theme.Palette, len(m.Theme.Palette)+1)
+		for name, hex := range m.Theme.Palette {
+			palette[name] = hex
+		}
+		palette[m.paletteName] = m.ColorInput.Hex()
+		return current.WithPalette(palette).ForBackground(m.variant)
+	}
+	item, ok := m.StyleList.SelectedItem().(styleItem)
+	if !ok {
+		return current.ForBackground(m.variant)
+	}
+
+	selected := item.Style
+	for i, item := range m.StyleList.Items() {
+		if item.(styleItem).Style != selected {
+			continue
+		}
+
+		style := &current.Styles[i]
+		if (m.foreActive || m.backActive) && theme.ValidHexCode(m.ColorInput.Hex()) == nil {
+			if m.foreActive {
+				style.SetVariantFore(m.variant, m.ColorInput.Value())
+			} else {
+				style.SetVariantBack(m.variant, m.ColorInput.Value())
+			}
+		} else if m.filesActive {
+			style.SetFiles(m.FilesInput.Value())
+		}
+	}
+
+	return current.ForBackground(m.variant)
+}
+
+// previewPane lists the preview directory colored with the in-memory theme, inside of a border
+// height lines tall so it matches the editor beside it
+func (m ThemeModel) previewPane(height int) string {
+	var body string
+
+	colorizer, err := m.previewTheme().Colorizer()
+	switch {
+	case m.previewErr != nil:
+		body = ErrorStyle.Width(PreviewWidth - 2).Render(m.previewErr.Error())
+	case err != nil:
+		body = ErrorStyle.Width(PreviewWidth - 2).Render(err.Error())
+	case len(m.previewEntries) == 0:
+		body = HelpDescStyle.Render("Nothing to show")
+	default:
+		lines := strings.Split(listing.Render(m.previewEntries, colorizer, listing.Options{Width: PreviewWidth - 2}), "\n")
+		// Leave room for the header and footer
+		if len(lines) > height-5 {
+			lines = append(lines[:max(0, min(len(lines), height-6))], HelpDescStyle.Render("..."))
+		}
+		body = strings.Join(lines, "\n")
+	}
+
+	source := "Working Directory"
+	if m.previewExample {
+		source = "Example Directory"
+	}
+	header := lipgloss.PlaceHorizontal(PreviewWidth, lipgloss.Center, TitleStyle.Render("Preview")+"\n"+SubtitleStyle.Render(source))
+	footer := lipgloss.PlaceHorizontal(PreviewWidth, lipgloss.Center, HelpKeyStyle.Render("e")+" "+HelpDescStyle.Render("Switch Directory"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", body)
+	content = lipgloss.PlaceVertical(height-1, lipgloss.Top, content) + "\n" + footer
+	pane := ViewportBorder.Width(PreviewWidth).Height(height)
+
+	// Line the pane up with the editor's border, below the program header
+	return lipgloss.NewStyle().MarginTop(lipgloss.Height(ProgramHeader())).Render(pane.Render(content))
+}
@@ -1,35 +1,28 @@
 package tui
 
 import (
-	"errors"
 	"fmt"
-	"log"
 	"os"
-	"regexp"
-	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/muesli/termenv"
 	"go.dalton.dog/stylish/internal/styling"
 	"golang.org/x/term"
 )
 
-// HexCodePattern will regex match any 6 digit hexcode
-const HexCodePattern = "[0-9a-fA-F]{6}"
-
 const ConstWidth = 35
 const ConstHeight = 27
 
-var EightBitMode = false
 var DefaultTermFore lipgloss.Color
 var DefaultTermBack lipgloss.Color
 
 var ViewportBorder = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#4400FF")).Height(ConstHeight)
 
 var TitleStyle = lipgloss.NewStyle().Underline(true).Bold(true).Italic(true)
+var ErrorStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF1155")).Width(ConstWidth - 2).Align(lipgloss.Center)
 var SubtitleStyle = lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#888888"))
+var WarningStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFAA00"))
 
 var HelpKeyStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
 	Light: "#909090",
@@ -41,6 +34,16 @@ var HelpDescStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{
 	Dark:  "#4A4A4A",
 })
 
+var ActiveAttrStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{
+	Light: "#1A1A1A",
+	Dark:  "#DDDDDD",
+})
+
+var InactiveAttrStyle = HelpDescStyle
+
+var PassBadgeStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#06D6A0"))
+var FailBadgeStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF1155"))
+
 var FocusedAreaStyle = textarea.Style{}
 
 var BlurredAreaStyle = textarea.Style{}
@@ -54,17 +57,17 @@ func GetItemDelgate() (del list.DefaultDelegate) {
 
 	del.Styles = styles
 
-	del.SetHeight(4)
+	del.SetHeight(5)
 
 	return del
 }
 
-func GetTermSize() (int, int) {
+func GetTermSize() (int, int, error) {
 	width, height, err := term.GetSize(int(os.Stdin.Fd()))
 	if err != nil {
-		log.Fatal(err)
+		return 0, 0, fmt.Errorf("getting terminal size: %w", err)
 	}
-	return width, height
+	return width, height, nil
 }
 
 func CenterHorz(msg string) string {
@@ -82,33 +85,11 @@ func ProgramHeader() string {
 	return lipgloss.NewStyle().PaddingLeft((ConstWidth-lipgloss.Width(title))/2 + 2).Render(title)
 }
 
-func RenderModel(body, footer string) string {
-	return Center(fmt.Sprintf("%v\n%v", ProgramHeader(), ViewportBorder.Render(fmt.Sprintf("%v\n%v", body, CenterHorz(footer)))))
-}
-
-func ValidHexCode(input string) error {
-	match, err := regexp.MatchString(HexCodePattern, input)
+// RenderModel lays out a model's body and footer beneath the program header.
+// If err is non-nil, it's shown in a banner above the footer.
+func RenderModel(body, footer string, err error) string {
 	if err != nil {
-		return err
-	}
-	if !match {
-		return errors.New("Enter a valid hex code")
+		footer = fmt.Sprintf("%v\n%v", ErrorStyle.Render(err.Error()), footer)
 	}
-
-	return nil
-
-}
-
-func HexToRGB(hex string) termenv.RGBColor {
-	if strings.HasPrefix(hex, "#") {
-		return termenv.RGBColor(hex)
-	} else {
-		return termenv.RGBColor("#" + hex)
-	}
-
-}
-
-func HexToEightBit(hex string) termenv.Color {
-	prof256 := termenv.ANSI256
-	return prof256.Convert(HexToRGB(hex))
+	return Center(fmt.Sprintf("%v\n%v", ProgramHeader(), ViewportBorder.Render(fmt.Sprintf("%v\n%v", body, CenterHorz(footer)))))
 }
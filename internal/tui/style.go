@@ -2,43 +2,70 @@ package tui
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
-	"gopkg.in/yaml.v3"
-
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/log"
 	"github.com/muesli/termenv"
-)
 
-type Style struct {
-	Theme string `yaml:"theme"`
-	Name  string `yaml:"name"`
+	"go.dalton.dog/stylish/theme"
+)
 
-	Bold  bool `yaml:"bold"`
-	Under bool `yaml:"under"`
-	Blink bool `yaml:"blink"`
+// styleItem wraps a theme.Style so it can be shown in a list
+type styleItem struct {
+	*theme.Style
+	// variant is the background whose colors are shown, or empty for the style's own
+	variant string
+	// simulation is the color vision deficiency the colors are shown with, or empty for none
+	simulation string
+	// profile is the terminal color profile the preview is downsampled to, and matcher how
+	profile termenv.Profile
+	matcher theme.Matcher
+	// defaultFore and defaultBack stand in for colors the style leaves to the terminal when checking contrast
+	defaultFore, defaultBack string
+}
 
-	Fore string `yaml:"fore"`
-	Back string `yaml:"back"`
+// newStyleItem wraps one of the theme's styles, showing it the way the editor currently is
+func (m ThemeModel) newStyleItem(style *theme.Style) styleItem {
+	fore, back := m.Theme.DefaultColors(m.variant)
+	if m.simulation != "" {
+		fore, back = theme.SimulateColor(fore, m.simulation), theme.SimulateColor(back, m.simulation)
+	}
+	return styleItem{
+		Style:       style,
+		variant:     m.variant,
+		simulation:  m.simulation,
+		profile:     m.profile,
+		matcher:     m.matcher,
+		defaultFore: fore,
+		defaultBack: back,
+	}
+}
 
-	FileTypes []string `yaml:"filetypes"`
+// shown is the style with the colors of the variant being shown swapped in, as they look with the simulation
+func (s styleItem) shown() theme.Style {
+	shown := s.ForBackground(s.variant).WithMatcher(s.matcher)
+	if s.simulation != "" {
+		shown = shown.Simulate(s.simulation)
+	}
+	return shown
 }
 
-// These functions fullfil the tea.DefaultItemValue interface
-func (s Style) Title() string {
-	return lipgloss.PlaceHorizontal(lipgloss.Width(s.Description()), lipgloss.Center, s.getPreview(s.Name))
+// These functions fullfil the list.DefaultItem interface
+func (s styleItem) Title() string {
+	title := s.getPreview(s.Name)
+	if source := s.Source(); source != theme.SourceLocal {
+		title += SubtitleStyle.Render(" (" + source.String() + ")")
+	}
+	return lipgloss.PlaceHorizontal(lipgloss.Width(s.Description()), lipgloss.Center, title)
 }
 
 // 3 Row description
-func (s Style) Description() string {
+func (s styleItem) Description() string {
 	// return s.threeRowDesc()
 	return s.twoColDesc()
 }
 
-func (s Style) threeRowDesc() string {
+func (s styleItem) threeRowDesc() string {
 	boxes := s.getCheckboxes()
 	topLine := fmt.Sprintf("%v | %v | %v", boxes["Bold"], boxes["Under"], boxes["Blink"])
 	// midLine := fmt.Sprintf("Fore: #%v | Back: #%v", s.Fore, s.Back)
@@ -51,35 +78,72 @@ func (s Style) threeRowDesc() string {
 	return outStr
 }
 
-func (s Style) twoColDesc() string {
+func (s styleItem) twoColDesc() string {
 	boxes := s.getCheckboxes()
-	var fore string
-	var back string
-	if s.Fore == "" {
-		fore = "DEFAULT"
-	} else {
-		fore = "#" + s.Fore
-	}
-	if s.Back == "" {
-		back = "DEFAULT"
-	} else {
-		back = "#" + s.Back
-	}
-	topLine := fmt.Sprintf("(1) %v | (f) Fore: %v ", boxes["Bold"], fore)
-	midLine := fmt.Sprintf("(2) %v | (b) Back: %v ", boxes["Under"], back)
-	botLine := fmt.Sprintf("(3) %v | (t) Filetypes: %v", boxes["Blink"], len(s.FileTypes))
-	outStr := fmt.Sprintf("%v\n%v\n%v\n", topLine, midLine, botLine)
+	// The labels show the colors as they're set, even while simulating how they look
+	set := s.ForBackground(s.variant).WithMatcher(s.matcher)
+	fore := colorLabel(set.Fore)
+	back := colorLabel(set.Back)
+	topLine := fmt.Sprintf("(1) %v | (f) Fore: %v%v", boxes["Bold"], fore, s.swatch(set.ResolvedFore(), set.DownsampledFore(s.downsampledProfile())))
+	midLine := fmt.Sprintf("(2) %v | (b) Back: %v%v", boxes["Under"], back, s.swatch(set.ResolvedBack(), set.DownsampledBack(s.downsampledProfile())))
+	botLine := fmt.Sprintf("(3) %v | (t) Filetypes: %v%v", boxes["Blink"], len(s.FileTypes), s.contrastBadge())
+	outStr := fmt.Sprintf("%v\n%v\n%v\n%v\n", topLine, midLine, botLine, s.extraAttrsLine())
 	// return lipgloss.PlaceHorizontal(lipgloss.Width(midLine), lipgloss.Center, outStr)
 	return outStr
 }
 
+// downsampledProfile is the profile the swatches compare the truecolor colors against: the
+// previewed profile, or 256 colors while previewing in truecolor
+func (s styleItem) downsampledProfile() termenv.Profile {
+	if s.profile == termenv.TrueColor {
+		return termenv.ANSI256
+	}
+	return s.profile
+}
+
+// swatch is a single cell split in two, with the truecolor color on the left and the
+// downsampled one on the right, so colors that change much when downsampled stand out
+func (s styleItem) swatch(hex string, downsampled termenv.Color) string {
+	if hex == "" || downsampled == nil {
+		return " "
+	}
+	return lipgloss.NewStyle().
+		Background(lipgloss.Color("#" + hex)).
+		Foreground(terminalColor(downsampled)).
+		Render("▐")
+}
+
+// contrastBadge marks whether the shown colors are readable against each other.
+// Styles that leave both colors to the terminal get no badge.
+func (s styleItem) contrastBadge() string {
+	contrast, ok := s.shown().Contrast(s.defaultFore, s.defaultBack)
+	if !ok {
+		return ""
+	}
+	if contrast.Pass() {
+		return PassBadgeStyle.Render(" ✓")
+	}
+	return FailBadgeStyle.Render(" ✗")
+}
+
+// colorLabel shows how a style color was set: a hex code, a palette reference, or the terminal default
+func colorLabel(color string) string {
+	switch {
+	case color == "":
+		return "DEFAULT"
+	case theme.IsPaletteRef(color):
+		return color
+	}
+	return "#" + color
+}
+
 func center(s string, w int) string {
 	return lipgloss.PlaceHorizontal(w, lipgloss.Center, s)
 }
 
-func (s Style) FilterValue() string { return s.Name }
+func (s styleItem) FilterValue() string { return s.Name }
 
-func (s Style) getCheckboxes() map[string]string {
+func (s styleItem) getCheckboxes() map[string]string {
 	outStr := make(map[string]string)
 	if s.Bold {
 		outStr["Bold"] = "✓ Bold "
@@ -102,177 +166,61 @@ func (s Style) getCheckboxes() map[string]string {
 	return outStr
 }
 
-func (s Style) getPreview(msg string) string {
-	var backColor lipgloss.Color
-	var foreColor lipgloss.Color
-	if s.Back == "" {
-		backColor = lipgloss.Color("")
-	} else {
-		backColor = lipgloss.Color("#" + s.Back)
-	}
-	if s.Fore == "" {
-		foreColor = lipgloss.Color("")
-	} else {
-		foreColor = lipgloss.Color("#" + s.Fore)
-	}
-
-	previewColor := lipgloss.NewStyle().Foreground(foreColor).Background(backColor).
-		Bold(s.Bold).Underline(s.Under).Blink(s.Blink)
-
-	return previewColor.Render(msg)
-}
-
-func (s *Style) ToggleBold() {
-	s.Bold = !s.Bold
-}
-
-func (s *Style) ToggleUnder() {
-	s.Under = !s.Under
-}
-
-func (s *Style) ToggleBlink() {
-	s.Blink = !s.Blink
-}
-
-func (s *Style) SetFore(fore string) {
-	s.Fore = fore
-}
-
-func (s *Style) SetBack(back string) {
-	s.Back = back
-}
-
-func (s *Style) SetFiles(files string) {
-	s.FileTypes = make([]string, 0)
-	if files == "" {
-		return
-	}
-
-	// Ensure we're not saving any blankline filetypes
-	tempTypes := strings.Split(files, "\n")
-	for _, newType := range tempTypes {
-		if newType != "" {
-			s.FileTypes = append(s.FileTypes, newType)
-
+// terminalColor turns a termenv color into the lipgloss color that renders the same way.
+// Missing colors, and colors the profile can't show, come out as the terminal default.
+func terminalColor(color termenv.Color) lipgloss.TerminalColor {
+	switch color := color.(type) {
+	case termenv.RGBColor:
+		return lipgloss.Color(string(color))
+	case termenv.ANSI256Color:
+		return lipgloss.ANSIColor(color)
+	case termenv.ANSIColor:
+		return lipgloss.ANSIColor(color)
+	}
+	return lipgloss.NoColor{}
+}
+
+// extraAttrsLine lists the attributes toggled by keys 4-9, highlighting the active ones
+func (s styleItem) extraAttrsLine() string {
+	attrs := []struct {
+		on    bool
+		label string
+	}{
+		{s.Italic, "Ital"},
+		{s.Dim, "Dim"},
+		{s.Reverse, "Rev"},
+		{s.Strike, "Strk"},
+		{s.Hidden, "Hide"},
+		{s.Overline, "Ovr"},
+	}
+
+	labels := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		if attr.on {
+			labels = append(labels, ActiveAttrStyle.Render(attr.label))
+		} else {
+			labels = append(labels, InactiveAttrStyle.Render(attr.label))
 		}
 	}
-}
 
-func NewStyle(themeName, styleName string) Style {
-	return Style{
-		Theme:     themeName,
-		Name:      styleName,
-		FileTypes: make([]string, 0),
-	}
+	return "(4-9) " + strings.Join(labels, " ")
 }
 
-func CopyStyle(style Style, newName string) Style {
-	newStyle := NewStyle(style.Theme, newName)
-
-	newStyle.Bold = style.Bold
-	newStyle.Blink = style.Blink
-	newStyle.Under = style.Under
-	newStyle.Fore = style.Fore
-	newStyle.Back = style.Back
-	newStyle.FileTypes = append(newStyle.FileTypes, style.FileTypes...)
-
-	return newStyle
-}
+func (s styleItem) getPreview(msg string) string {
+	shown := s.shown()
+	// Downsampled to the item's profile, so the preview looks the way the theme would in that terminal
+	foreColor := terminalColor(shown.DownsampledFore(s.profile))
+	backColor := terminalColor(shown.DownsampledBack(s.profile))
 
-func GetStyle(theme, styleName string) Style {
-	themePath := filepath.Join(ThemeConfigFolder, theme)
-	var outStyle Style
-	style := loadStyle(themePath, styleName)
-	if style == nil {
-		outStyle = NewStyle(theme, styleName)
-	} else {
-		outStyle = *style
-	}
-
-	return outStyle
-}
-
-func loadStyle(theme, styleName string) *Style {
-	file, err := os.Open(filepath.Join(ThemeConfigFolder, theme, styleName+".yaml"))
-	if err != nil {
-		return nil
-	}
-	defer file.Close()
-
-	var style *Style
-	decoder := yaml.NewDecoder(file)
-	if err := decoder.Decode(&style); err != nil {
-		return nil
-	}
-
-	return style
-}
-
-func (s Style) SaveStyle() {
-	path := filepath.Join(ThemeConfigFolder, s.Theme)
-	file, err := os.Create(filepath.Join(path, s.Name+".yaml"))
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer file.Close()
-
-	encoder := yaml.NewEncoder(file)
-	err = encoder.Encode(s)
-	if err != nil {
-		log.Fatal(err)
-	}
-}
-
-func (s Style) GetDirColorBlock() string {
-	if len(s.FileTypes) < 1 {
-		return ""
-	}
-
-	outStr := " # " + s.Name + "\n\n"
-
-	styleStr := ""
-
-	if s.Bold {
-		styleStr += "1;"
-	}
-
-	if s.Under {
-		styleStr += "4;"
-	}
-
-	if s.Blink {
-		styleStr += "5;"
-	}
-
-	if s.Fore != "" {
-		var fore termenv.Color
-		if EightBitMode {
-			fore = HexToEightBit(s.Fore)
-		} else {
-			fore = HexToRGB(s.Fore)
-		}
-		styleStr += fore.Sequence(false) + ";"
-	}
-
-	if s.Back != "" {
-		var back termenv.Color
-		if EightBitMode {
-			back = HexToEightBit(s.Back)
-		} else {
-			back = HexToRGB(s.Back)
-		}
-		styleStr += back.Sequence(true) + ";"
-	}
-
-	styleStr = strings.TrimSuffix(styleStr, ";")
+	previewColor := lipgloss.NewStyle().Foreground(foreColor).Background(backColor).
+		Bold(s.Bold).Underline(s.Under).Blink(s.Blink).
+		Faint(s.Dim).Italic(s.Italic).Reverse(s.Reverse).Strikethrough(s.Strike)
 
-	for _, file := range s.FileTypes {
-		if file == "" {
-			continue
-		}
-		outStr += fmt.Sprintf("%v %v\n", file, styleStr)
+	// lipgloss has no overline, so wrap the message in the raw SGR codes instead.
+	// Hidden is intentionally not previewed, as it would make the style unreadable.
+	if s.Overline {
+		msg = "\x1b[53m" + msg + "\x1b[55m"
 	}
 
-	return outStr + "\n"
+	return previewColor.Render(msg)
 }
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -12,14 +13,19 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/muesli/termenv"
+
+	"go.dalton.dog/stylish/internal/listing"
+	"go.dalton.dog/stylish/internal/profile"
+	"go.dalton.dog/stylish/theme"
 )
 
 type ThemeModel struct {
-	Theme     Theme
+	Theme     theme.Theme
 	StyleList list.Model
 
 	NameInput  textinput.Model
-	ColorInput textinput.Model
+	ColorInput ColorPicker
 	FilesInput textarea.Model
 
 	showSystemFileTypes bool
@@ -32,48 +38,75 @@ type ThemeModel struct {
 	nameActive   bool
 	isCopying    bool
 
+	warningsActive bool
+	// problems are the theme's lint warnings, rechecked whenever a style, the palette or the manifest is saved
+	problems []theme.Problem
+
+	// variant is the background whose colors are shown and edited, or empty for the styles' own
+	variant string
+	// simulation is the color vision deficiency the styles are shown with, or empty for none
+	simulation string
+	// profile is the terminal color profile the styles are previewed with
+	profile termenv.Profile
+	// matcher picks the 256 colors the styles are previewed with, and compareActive shows how each matcher picks them
+	matcher       theme.Matcher
+	compareActive bool
+	compareOffset int
+
+	paletteActive bool
+	paletteMode   paletteMode
+	paletteCursor int
+	paletteName   string // Entry whose color is being picked
+
+	previewActive  bool
+	previewExample bool
+	previewEntries []listing.Entry
+	previewErr     error
+
+	err error
+
 	help help.Model
 }
 
-func NewThemeModel(theme Theme) ThemeModel {
+func NewThemeModel(t theme.Theme) ThemeModel {
 	newHelp := help.New()
 	newHelp.ShowAll = true
 	newHelp.Width = ConstWidth - 3
-	var styles []list.Item
-	for _, style := range theme.Styles {
-		log.Debug(style)
-		styles = append(styles, list.Item(&style))
-	}
-	del := GetItemDelgate()
-	list := list.New(styles, del, ConstWidth, ConstHeight)
-	list.Title = "Manage Styles for " + theme.Name
-	list.SetShowStatusBar(false)
-	list.SetShowHelp(false)
-	list.SetShowTitle(false)
-	list.InfiniteScrolling = true
 
 	nameInput := textinput.New()
 	nameInput.Placeholder = "New Style Name"
 
-	colorInput := textinput.New()
-	colorInput.CharLimit = 6
-	colorInput.Prompt = "#"
-	colorInput.Validate = ValidHexCode
+	colorInput := NewColorPicker()
 
 	fileArea := textarea.New()
-	fileArea.Placeholder = ".mp3\n.ogg\n.wav\n.txt"
+	fileArea.Placeholder = ".mp3\n.ogg\n*.tar.gz\nfile:Makefile\nDIR"
 	fileArea.SetWidth(ConstWidth - 8)
 	fileArea.SetHeight(ConstHeight - 10)
 
-	return ThemeModel{
-		Theme:      theme,
+	m := ThemeModel{
+		Theme:      t,
 		ColorInput: colorInput,
 		NameInput:  nameInput,
 		FilesInput: fileArea,
-		StyleList:  list,
 		help:       newHelp,
+		profile:    profile.Detect(),
 	}
 
+	var styles []list.Item
+	for _, style := range t.Styles {
+		log.Debug(style)
+		styles = append(styles, list.Item(m.newStyleItem(&style)))
+	}
+	del := GetItemDelgate()
+	m.StyleList = list.New(styles, del, ConstWidth, ConstHeight)
+	m.StyleList.Title = "Manage Styles for " + t.Name
+	m.StyleList.SetShowStatusBar(false)
+	m.StyleList.SetShowHelp(false)
+	m.StyleList.SetShowTitle(false)
+	m.StyleList.InfiniteScrolling = true
+	m.relint()
+
+	return m
 }
 
 func (m ThemeModel) Init() tea.Cmd {
@@ -81,16 +114,29 @@ func (m ThemeModel) Init() tea.Cmd {
 }
 
 func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var style *Style
-	if len(m.StyleList.Items()) > 0 {
-		style = m.StyleList.SelectedItem().(*Style)
+	// Nothing is selected when the list is empty, or its filter doesn't match anything
+	var style *theme.Style
+	if item, ok := m.StyleList.SelectedItem().(styleItem); ok {
+		style = item.Style
+	}
+
+	if m.compareActive {
+		return m.updateCompare(msg)
+	}
+	if m.paletteActive {
+		return m.updatePalette(msg)
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.err = nil
+		// Keys are typed into the filter while the list is being filtered
+		if m.StyleList.FilterState() == list.Filtering {
+			break
+		}
 		switch msg.String() {
 		case "n", "c": // New style
-			if !m.isAnythingActive() {
+			if !m.isAnythingActive() && (msg.String() == "n" || style != nil) {
 				if msg.String() == "c" {
 					m.isCopying = true
 				}
@@ -101,40 +147,138 @@ func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.deleteActive = false
 				return m, nil
 			}
-		case "d": // Delete style
+		case "w": // Toggle lint warnings
+			if !m.isAnythingActive() {
+				m.warningsActive = true
+				return m, nil
+			} else if m.warningsActive {
+				m.warningsActive = false
+				return m, nil
+			}
+		case "p": // Toggle preview pane
+			if !m.isAnythingActive() {
+				m.previewActive = !m.previewActive
+				if m.previewActive {
+					m.loadPreview()
+				}
+				return m, nil
+			}
+		case "P": // Edit the theme's palette
+			if !m.isAnythingActive() {
+				m.paletteActive = true
+				m.paletteMode = paletteBrowsing
+				return m, nil
+			}
+		case "v": // Cycle between editing the styles' own, dark, and light colors
+			if !m.isAnythingActive() {
+				m.cycleVariant()
+				return m, nil
+			}
+		case "s": // Cycle through simulated color vision deficiencies
+			if !m.isAnythingActive() {
+				m.cycleSimulation()
+				return m, nil
+			}
+		case "m": // Cycle through the color profiles the styles are previewed with
+			if !m.isAnythingActive() {
+				m.cycleProfile()
+				return m, nil
+			}
+		case "C": // Compare how each matcher downsamples the styles' colors
 			if !m.isAnythingActive() {
+				m.compareActive = true
+				m.compareOffset = 0
+				return m, nil
+			}
+		case "e": // Switch the preview between the working directory and the example directory
+			if !m.isAnythingActive() && m.previewActive {
+				m.previewExample = !m.previewExample
+				m.loadPreview()
+				return m, nil
+			}
+		case "d": // Delete style
+			if !m.isAnythingActive() && style != nil {
 				m.deleteActive = true
 				return m, nil
 			}
+		case "K", "J": // Move style up or down in the order it's applied
+			if !m.isAnythingActive() && style != nil && m.StyleList.FilterState() == list.Unfiltered {
+				return m.moveStyle(msg.String() == "J")
+			}
+		case "r": // Revert an overridden style to the one it inherits
+			if !m.isAnythingActive() && style != nil && style.Source() == theme.SourceOverridden {
+				reverted, err := m.Theme.RevertStyle(style.Name)
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				*style = reverted
+				m.relint()
+				return m, nil
+			}
 		case "1": // Toggle Bold
-			if !m.isAnythingActive() {
+			if !m.isAnythingActive() && style != nil {
 				style.ToggleBold()
-				style.SaveStyle()
+				m.err = m.saveStyle(style)
 			}
 		case "2": // Toggle Underline
-			if !m.isAnythingActive() {
+			if !m.isAnythingActive() && style != nil {
 				style.ToggleUnder()
-				style.SaveStyle()
+				m.err = m.saveStyle(style)
 			}
 		case "3": // Toggle Blinking
-			if !m.isAnythingActive() {
-				m.StyleList.SelectedItem().(*Style).ToggleBlink()
-				style.SaveStyle()
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleBlink()
+				m.err = m.saveStyle(style)
+			}
+		case "4": // Toggle Italic
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleItalic()
+				m.err = m.saveStyle(style)
+			}
+		case "5": // Toggle Dim
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleDim()
+				m.err = m.saveStyle(style)
+			}
+		case "6": // Toggle Reverse
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleReverse()
+				m.err = m.saveStyle(style)
+			}
+		case "7": // Toggle Strikethrough
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleStrike()
+				m.err = m.saveStyle(style)
+			}
+		case "8": // Toggle Hidden
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleHidden()
+				m.err = m.saveStyle(style)
+			}
+		case "9": // Toggle Overline
+			if !m.isAnythingActive() && style != nil {
+				style.ToggleOverline()
+				m.err = m.saveStyle(style)
 			}
 		case "f": // Edit Foreground
-			if !m.isAnythingActive() {
-				m.ColorInput.SetValue(style.Fore)
+			if !m.isAnythingActive() && style != nil {
+				m.ColorInput.Palette = m.Theme.Palette
+				m.ColorInput.SetValue(style.ForBackground(m.variant).Fore)
+				m.ColorInput.Theme = m.themeColors()
 				m.foreActive = true
 				return m, m.ColorInput.Focus()
 			}
 		case "b": // Edit Background
-			if !m.isAnythingActive() {
-				m.ColorInput.SetValue(style.Back)
+			if !m.isAnythingActive() && style != nil {
+				m.ColorInput.Palette = m.Theme.Palette
+				m.ColorInput.SetValue(style.ForBackground(m.variant).Back)
+				m.ColorInput.Theme = m.themeColors()
 				m.backActive = true
 				return m, m.ColorInput.Focus()
 			}
 		case "t": // Edit filetypes
-			if !m.isAnythingActive() {
+			if !m.isAnythingActive() && style != nil {
 				m.FilesInput.SetValue(strings.Join(style.FileTypes, "\n"))
 				m.filesActive = true
 				return m, m.FilesInput.Focus()
@@ -142,15 +286,17 @@ func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "y":
 			if m.deleteActive {
 				m.StyleList.RemoveItem(m.StyleList.Index())
-				m.Theme.RemoveStyle(style.Name)
+				m.err = m.Theme.RemoveStyle(style.Name)
+				m.relint()
 				m.deleteActive = false
 				return m, nil
 			}
 		case "esc": // Close theme editor
-			if !m.isAnythingActive() {
-				m.Theme.GenerateDirColors()
-				model := NewLandingModel()
-				return model, model.Init()
+			if m.warningsActive {
+				m.warningsActive = false
+				return m, nil
+			} else if !m.isAnythingActive() {
+				return m.exitToLanding()
 			}
 		case "ctrl+h": // Show detailed system filetypes helptext
 			if m.filesActive {
@@ -161,23 +307,31 @@ func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.isAnythingActive() {
 				if m.nameActive {
 					val := m.NameInput.Value()
-					if m.Theme.DoesStyleExist(val) {
-						m.deactivateInputs()
-						m.NameInput.SetValue("")
+					// Leave the name open to fix, the same as filetypes
+					if err := m.Theme.ValidStyleName(val); err != nil {
+						m.err = err
 						return m, nil
 					}
-					var newStyle Style
+					var newStyle theme.Style
 					if m.isCopying {
-						newStyle = CopyStyle(*style, val)
-						newStyle.SaveStyle()
+						newStyle = theme.CopyStyle(*style, val)
+						if err := newStyle.SaveStyle(); err != nil {
+							m.err = err
+							m.deactivateInputs()
+							return m, nil
+						}
 
 					} else {
-						newStyle = NewStyle(m.Theme.Name, val)
+						newStyle = m.Theme.NewStyle(val)
 
 					}
 					m.Theme.Styles = append(m.Theme.Styles, newStyle)
-					m.StyleList.InsertItem(len(m.StyleList.Items()), &newStyle)
+					if len(m.Theme.Manifest.Order) > 0 {
+						m.err = m.Theme.SetOrder(append(m.Theme.Manifest.Order, val))
+					}
+					m.StyleList.InsertItem(len(m.StyleList.Items()), m.newStyleItem(&newStyle))
 					m.StyleList.CursorDown()
+					m.relint()
 					var cmd tea.Cmd
 					m.StyleList, cmd = m.StyleList.Update(msg)
 					m.deactivateInputs()
@@ -186,13 +340,22 @@ func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				}
 				if m.backActive {
-					style.SetBack(m.ColorInput.Value())
+					style.SetVariantBack(m.variant, m.ColorInput.Value())
+					m.ColorInput.Recent = AddRecent(m.ColorInput.Recent, m.ColorInput.Hex())
 				} else if m.foreActive {
-					style.SetFore(m.ColorInput.Value())
+					style.SetVariantFore(m.variant, m.ColorInput.Value())
+					m.ColorInput.Recent = AddRecent(m.ColorInput.Recent, m.ColorInput.Hex())
 				} else if m.filesActive {
+					// Validate against a copy, so the textarea stays open to fix any mistakes
+					candidate := *style
+					candidate.SetFiles(m.FilesInput.Value())
+					if _, err := candidate.ParseFileTypes(); err != nil {
+						m.err = err
+						return m, nil
+					}
 					style.SetFiles(m.FilesInput.Value())
 				}
-				style.SaveStyle()
+				m.err = m.saveStyle(style)
 				m.deactivateInputs()
 
 				return m, nil
@@ -202,20 +365,22 @@ func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.deactivateInputs()
 				return m, nil
 			} else {
-				m.Theme.GenerateDirColors()
-				model := NewLandingModel()
-				return model, model.Init()
+				return m.exitToLanding()
 			}
 		case "ctrl+q": // Clear value to default
+			if style == nil || !(m.foreActive || m.backActive || m.filesActive) {
+				m.deactivateInputs()
+				return m, nil
+			}
 			if m.foreActive {
-				style.SetFore("")
+				style.SetVariantFore(m.variant, "")
 			} else if m.backActive {
-				style.SetBack("")
-			} else if m.filesActive {
+				style.SetVariantBack(m.variant, "")
+			} else {
 				style.SetFiles("")
 			}
 			m.deactivateInputs()
-			style.SaveStyle()
+			m.err = m.saveStyle(style)
 			return m, nil
 		}
 	}
@@ -231,50 +396,238 @@ func (m ThemeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m ThemeModel) View() string {
+	editor := m.editorView()
+	if m.previewActive {
+		// The editor's border sits below the program header
+		height := lipgloss.Height(editor) - lipgloss.Height(ProgramHeader()) - 2
+		return lipgloss.JoinHorizontal(lipgloss.Top, editor, m.previewPane(height))
+	}
+	return editor
+}
+
+func (m ThemeModel) editorView() string {
 	if !m.isAnythingActive() {
-		listHeader := CenterHorz(TitleStyle.Render("Theme Styles") + "\n" + SubtitleStyle.Render("Theme: "+m.Theme.Name))
-		return RenderModel(listHeader+"\n"+m.StyleList.View(), m.help.View(themeKeys))
+		subtitle := SubtitleStyle.Render("Theme: " + m.Theme.Name)
+		if m.variant != "" {
+			subtitle += SubtitleStyle.Render(" · " + m.variant + " colors")
+		}
+		if m.simulation != "" {
+			subtitle += SubtitleStyle.Render(" · " + m.simulation)
+		}
+		if m.profile != termenv.TrueColor {
+			subtitle += SubtitleStyle.Render(" · " + profile.Name(m.profile) + " colors")
+		}
+		if m.selectedMatcher() != theme.MatchRGB {
+			subtitle += SubtitleStyle.Render(" · " + string(m.matcher))
+		}
+		if count := len(m.problems); count == 1 {
+			subtitle += " " + WarningStyle.Render("(1 warning)")
+		} else if count > 1 {
+			subtitle += " " + WarningStyle.Render(fmt.Sprintf("(%v warnings)", count))
+		}
+		listHeader := CenterHorz(TitleStyle.Render("Theme Styles") + "\n" + subtitle)
+		return RenderModel(listHeader+"\n"+m.StyleList.View(), m.help.View(themeKeys), m.err)
+	} else if m.paletteActive {
+		return m.getPaletteModel()
+	} else if m.warningsActive {
+		return m.getWarningsModel()
+	} else if m.compareActive {
+		return m.getCompareModel()
 	} else if m.deleteActive {
-		return RenderModel(Center(TitleStyle.Render("Delete this style? (y/n)")), "")
+		return RenderModel(Center(TitleStyle.Render("Delete this style? (y/n)")), "", m.err)
 	} else if m.foreActive || m.backActive {
 		return m.getColorModel()
 	} else if m.filesActive {
 		return RenderModel(fmt.Sprintf("%v\n\n%v",
-			CenterHorz(TitleStyle.Render("Filetypes")), CenterHorz(m.FilesInput.View())), m.getFileAreaHelpText())
+			CenterHorz(TitleStyle.Render("Filetypes")), CenterHorz(m.FilesInput.View())), m.getFileAreaHelpText(), m.err)
 	} else if m.nameActive {
 		return RenderModel(fmt.Sprintf("%v\n\n%v\n",
-			CenterHorz(TitleStyle.Render("New Style")), CenterHorz(m.NameInput.View())), m.getEditHelpTextNoClear())
+			CenterHorz(TitleStyle.Render("New Style")), CenterHorz(m.NameInput.View())), m.getEditHelpTextNoClear(), m.err)
 	}
 
 	return ""
 }
 
 func (m ThemeModel) getColorModel() string {
-	var titleStr string
+	title := "Background Color"
 	if m.foreActive {
-		titleStr = TitleStyle.Render("Foreground Color")
-	} else {
-		titleStr = TitleStyle.Render("Background Color")
+		title = "Foreground Color"
 	}
+	if m.variant != "" {
+		title += " (" + m.variant + ")"
+	}
+	return m.colorPickerView(title, m.getEditHelpText())
+}
+
+// colorPickerView renders the color picker under title, with a swatch of the picked color
+func (m ThemeModel) colorPickerView(title, editHelp string) string {
+	titleStr := TitleStyle.Render(title)
 
 	footerString := ""
-	if m.ColorInput.Err != nil {
-		footerString = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF1155")).Render(m.ColorInput.Err.Error())
+	if m.ColorInput.Err() != nil {
+		footerString = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF1155")).Render(m.ColorInput.Err().Error())
 	} else {
-		footerString = lipgloss.NewStyle().Foreground(lipgloss.Color("#" + m.ColorInput.Value())).Render(strings.Repeat("█", 18))
+		footerString = lipgloss.NewStyle().Foreground(lipgloss.Color("#" + m.ColorInput.Hex())).Render(strings.Repeat("█", 18))
 	}
 
-	outStr := fmt.Sprintf("%v\n\n%v\n\n%v\n\n%v",
+	keyStyle := m.help.Styles.FullKey
+	descStyle := m.help.Styles.FullDesc
+	pickerHelp := keyStyle.Render("↑/↓") + descStyle.Render(" Row  ") +
+		keyStyle.Render("←/→") + descStyle.Render(" Adjust  ") +
+		keyStyle.Render("shift") + descStyle.Render(" x10")
+
+	outStr := fmt.Sprintf("%v\n\n%v\n\n%v\n\n%v\n%v",
 		CenterHorz(titleStr),
-		CenterHorz(m.ColorInput.View()),
+		m.ColorInput.View(),
 		CenterHorz(footerString),
-		m.getEditHelpText())
+		CenterHorz(pickerHelp),
+		editHelp)
+
+	return RenderModel(outStr, "", m.err)
+}
+
+// themeColors lists every valid color used by the theme's styles, in order and without repeats
+func (m ThemeModel) themeColors() []string {
+	var colors []string
+	seen := make(map[string]bool)
+	for _, style := range m.currentTheme().Styles {
+		for _, color := range []string{style.ResolvedFore(), style.ResolvedBack()} {
+			color = strings.ToUpper(color)
+			if theme.ValidHexCode(color) == nil && !seen[color] {
+				seen[color] = true
+				colors = append(colors, color)
+			}
+		}
+	}
+	return colors
+}
+
+// getWarningsModel lists everything `stylish lint` would report for the theme as it currently stands
+func (m ThemeModel) getWarningsModel() string {
+	problems := m.problems
+
+	var body strings.Builder
+	if len(problems) == 0 {
+		body.WriteString(CenterHorz(HelpDescStyle.Render("No problems found")))
+	}
+	wrap := lipgloss.NewStyle().Width(ConstWidth - 4).PaddingLeft(2)
+	for _, problem := range problems {
+		if problem.Style != "" {
+			body.WriteString(WarningStyle.PaddingLeft(2).Render(problem.Style) + "\n")
+		}
+		body.WriteString(wrap.Render(problem.Message) + "\n\n")
+	}
+
+	footer := HelpKeyStyle.Render("w/esc") + " " + HelpDescStyle.Render("Close")
+	return RenderModel(fmt.Sprintf("%v\n\n%v", CenterHorz(TitleStyle.Render("Warnings")), body.String()), footer, m.err)
+}
+
+// saveStyle saves one of the theme's styles, then rechecks the theme's warnings
+func (m *ThemeModel) saveStyle(style *theme.Style) error {
+	err := style.SaveStyle()
+	m.relint()
+	return err
+}
+
+// relint rechecks the theme's warnings. Linting resolves the palette and the extended theme,
+// so it's only done when something is saved rather than on every render.
+func (m *ThemeModel) relint() {
+	m.problems = m.currentTheme().Lint()
+}
+
+// currentTheme rebuilds the theme from the list items, which hold every edit made in this session
+func (m ThemeModel) currentTheme() theme.Theme {
+	current := m.Theme
+	current.Styles = nil
+	for _, item := range m.StyleList.Items() {
+		current.Styles = append(current.Styles, *item.(styleItem).Style)
+	}
+	return current
+}
 
-	return RenderModel(outStr, "")
+// cycleVariant moves on to showing and editing the next set of colors: the styles' own, then
+// their dark variants, then their light variants
+func (m *ThemeModel) cycleVariant() {
+	switch m.variant {
+	case "":
+		m.variant = theme.BackgroundDark
+	case theme.BackgroundDark:
+		m.variant = theme.BackgroundLight
+	default:
+		m.variant = ""
+	}
+	m.refreshStyleItems()
+}
+
+// cycleSimulation moves on to showing the styles as they look with the next color vision
+// deficiency, then back to how they normally look. Nothing about the styles is changed.
+func (m *ThemeModel) cycleSimulation() {
+	i := slices.Index(theme.Simulations, m.simulation)
+	if i+1 < len(theme.Simulations) {
+		m.simulation = theme.Simulations[i+1]
+	} else {
+		m.simulation = ""
+	}
+	m.refreshStyleItems()
+}
+
+// cycleProfile moves on to previewing the styles with the next color profile, from truecolor
+// down to none at all, then back to truecolor
+func (m *ThemeModel) cycleProfile() {
+	if m.profile == termenv.Ascii {
+		m.profile = termenv.TrueColor
+	} else {
+		m.profile++
+	}
+	m.refreshStyleItems()
+}
+
+// refreshStyleItems rewraps every style in the list, so they're shown with the current variant and simulation
+func (m *ThemeModel) refreshStyleItems() {
+	for i, item := range m.StyleList.Items() {
+		m.StyleList.SetItem(i, m.newStyleItem(item.(styleItem).Style))
+	}
+}
+
+// moveStyle swaps the selected style with the one below (or above) it, and saves the new order
+// to the theme's manifest. Later styles win when two claim the same filetype.
+func (m ThemeModel) moveStyle(down bool) (tea.Model, tea.Cmd) {
+	items := m.StyleList.Items()
+	from := m.StyleList.Index()
+	to := from - 1
+	if down {
+		to = from + 1
+	}
+	if to < 0 || to >= len(items) {
+		return m, nil
+	}
+
+	items[from], items[to] = items[to], items[from]
+	cmd := m.StyleList.SetItems(items)
+	m.StyleList.Select(to)
+
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.(styleItem).Name)
+	}
+	m.err = m.Theme.SetOrder(names)
+	m.relint()
+
+	return m, cmd
+}
+
+// exitToLanding regenerates the theme's .dircolors file and returns to the landing screen,
+// carrying over any error that occurred along the way
+func (m ThemeModel) exitToLanding() (tea.Model, tea.Cmd) {
+	model := NewLandingModel()
+	if err := m.currentTheme().GenerateDirColors(); err != nil {
+		model.err = err
+	}
+	return model, model.Init()
 }
 
 func (m ThemeModel) isAnythingActive() bool {
-	return m.backActive || m.foreActive || m.filesActive || m.nameActive || m.deleteActive
+	return m.backActive || m.foreActive || m.filesActive || m.nameActive || m.deleteActive || m.warningsActive || m.paletteActive || m.compareActive
 }
 
 func (m *ThemeModel) deactivateInputs() {
@@ -283,6 +636,9 @@ func (m *ThemeModel) deactivateInputs() {
 	m.nameActive = false
 	m.isCopying = false
 	m.filesActive = false
+	m.warningsActive = false
+	m.paletteActive = false
+	m.compareActive = false
 
 	m.ColorInput.Blur()
 	m.FilesInput.Blur()
@@ -295,10 +651,20 @@ type themeKeymap struct {
 	Select key.Binding
 	Quit   key.Binding
 
-	Delete key.Binding
-	New    key.Binding
-	Copy   key.Binding
-	Filter key.Binding
+	Delete   key.Binding
+	New      key.Binding
+	Copy     key.Binding
+	Filter   key.Binding
+	Attrs    key.Binding
+	Warnings key.Binding
+	Preview  key.Binding
+	Palette  key.Binding
+	Revert   key.Binding
+	Reorder  key.Binding
+	Variant  key.Binding
+	Simulate key.Binding
+	Profile  key.Binding
+	Compare  key.Binding
 }
 
 func (k themeKeymap) ShortHelp() []key.Binding {
@@ -307,8 +673,8 @@ func (k themeKeymap) ShortHelp() []key.Binding {
 
 func (k themeKeymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Quit},
-		{k.New, k.Delete, k.Copy, k.Filter},
+		{k.Up, k.Down, k.Quit, k.Attrs, k.Preview, k.Reorder, k.Revert, k.Simulate, k.Compare},
+		{k.New, k.Delete, k.Copy, k.Filter, k.Warnings, k.Palette, k.Variant, k.Profile},
 	}
 }
 
@@ -341,6 +707,46 @@ var themeKeys = themeKeymap{
 		key.WithKeys("/"),
 		key.WithHelp("/", "Filter"),
 	),
+	Attrs: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "Attrs"),
+	),
+	Warnings: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "Warnings"),
+	),
+	Preview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "Preview"),
+	),
+	Palette: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "Palette"),
+	),
+	Revert: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "Revert"),
+	),
+	Reorder: key.NewBinding(
+		key.WithKeys("K", "J"),
+		key.WithHelp("K/J", "Reorder"),
+	),
+	Variant: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "Light/Dark"),
+	),
+	Simulate: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "Colorblind"),
+	),
+	Profile: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "Color Mode"),
+	),
+	Compare: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "Matchers"),
+	),
 }
 
 func (m ThemeModel) getEditHelpTextNoClear() string {
@@ -389,6 +795,11 @@ func (m ThemeModel) getInitStrings() string {
 	outStr += CenterHorz(keyStyle.Render("SETUID")+descStyle.Render(" File w/ u+s             ")) + "\n"
 	outStr += CenterHorz(keyStyle.Render("SETGID")+descStyle.Render(" File w/ g+s             ")) + "\n"
 	outStr += CenterHorz(keyStyle.Render("STICKY")+descStyle.Render(" Dir w/ +t, no o or w    ")) + "\n"
+	outStr += CenterHorz(keyStyle.Render("eza:ur")+descStyle.Render(" eza UI element (README) ")) + "\n\n"
+	outStr += CenterHorz(TitleStyle.Render("Other Entries")) + "\n"
+	outStr += CenterHorz(keyStyle.Render("          .go")+descStyle.Render(" Extension  ")) + "\n"
+	outStr += CenterHorz(keyStyle.Render("     *.tar.gz")+descStyle.Render(" Name suffix")) + "\n"
+	outStr += CenterHorz(keyStyle.Render("file:Makefile")+descStyle.Render(" Exact name ")) + "\n"
 	// outStr += CenterHorz(keyStyle.Render("MISSING")+descStyle.Render(" Missing Files")) + "\n"
 	// outStr += CenterHorz(keyStyle.Render("CAPABILITY")+descStyle.Render(" File w/ capability")) + "\n"
 	// outStr += CenterHorz(keyStyle.Render("MULTIHARDLINK")+descStyle.Render(" File w/ >1 Link")) + "\n"
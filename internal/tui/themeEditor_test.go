@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// TestEditorWithoutSelection sends every key that acts on the selected style to an editor
+// that has no styles, which shouldn't panic or try to save anything
+func TestEditorWithoutSelection(t *testing.T) {
+	var m tea.Model = NewThemeModel(theme.Theme{Name: "empty"})
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyCtrlQ},
+		{Type: tea.KeyRunes, Runes: []rune("f")},
+		{Type: tea.KeyCtrlQ},
+		{Type: tea.KeyRunes, Runes: []rune("b")},
+		{Type: tea.KeyRunes, Runes: []rune("t")},
+		{Type: tea.KeyRunes, Runes: []rune("1")},
+		{Type: tea.KeyRunes, Runes: []rune("9")},
+		{Type: tea.KeyRunes, Runes: []rune("c")},
+		{Type: tea.KeyRunes, Runes: []rune("d")},
+		{Type: tea.KeyRunes, Runes: []rune("y")},
+		{Type: tea.KeyRunes, Runes: []rune("r")},
+		{Type: tea.KeyRunes, Runes: []rune("K")},
+		{Type: tea.KeyCtrlQ},
+	}
+	for _, key := range keys {
+		m, _ = m.Update(key)
+		_ = m.View()
+	}
+
+	editor := m.(ThemeModel)
+	if editor.err != nil {
+		t.Errorf("err = %v, want nil", editor.err)
+	}
+	if editor.isAnythingActive() {
+		t.Error("an input was left open without a style to edit")
+	}
+}
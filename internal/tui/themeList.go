@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -11,27 +12,77 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
+
+	"go.dalton.dog/stylish/theme"
 )
 
 type LandingModel struct {
-	ThemeList    list.Model
-	ThemeInput   textinput.Model
-	InputActive  bool
-	isCopying    bool
-	themeToCopy  string
-	DeleteActive bool
+	ThemeList     list.Model
+	ThemeInput    textinput.Model
+	InputActive   bool
+	isCopying     bool
+	themeToCopy   string
+	isExtending   bool
+	themeToExtend string
+	DeleteActive  bool
+
+	ImportInput  textinput.Model
+	importActive bool
+	isImporting  bool
+	importData   string
+
+	err error
 
 	keys landingKeymap
 	help help.Model
 }
 
+// themeItem wraps a theme.Theme so it can be shown in the landing screen's list
+type themeItem struct {
+	theme.Theme
+}
+
+// These functions fullfil the list.DefaultItem interface
+func (t themeItem) FilterValue() string { return t.Name + " " + t.Manifest.Title }
+
+// Title is the theme's display name from its manifest, falling back to its folder name
+func (t themeItem) Title() string {
+	if t.Manifest.Title != "" {
+		return t.Manifest.Title
+	}
+	return t.Name
+}
+
+// Description sums up the theme's manifest in a single line
+func (t themeItem) Description() string {
+	var parts []string
+	if t.Manifest.Title != "" && t.Manifest.Title != t.Name {
+		parts = append(parts, t.Name)
+	}
+	if t.Manifest.Description != "" {
+		parts = append(parts, t.Manifest.Description)
+	} else {
+		parts = append(parts, fmt.Sprintf("Styles loaded: %v", len(t.Styles)))
+	}
+	if t.Manifest.Author != "" {
+		parts = append(parts, "by "+t.Manifest.Author)
+	}
+	if t.Manifest.Background != "" {
+		parts = append(parts, t.Manifest.Background)
+	}
+	if t.Manifest.Extends != "" {
+		parts = append(parts, "extends "+t.Manifest.Extends)
+	}
+	return strings.Join(parts, " · ")
+}
+
 func NewLandingModel() LandingModel {
 	log.Debug("Trying to create landing model")
 
-	themes := GetAllThemes()
+	themes, err := theme.GetAllThemes()
 	var items []list.Item
 	for _, t := range themes {
-		items = append(items, list.Item(t))
+		items = append(items, list.Item(themeItem{t}))
 	}
 
 	l := list.New(items, list.NewDefaultDelegate(), ConstWidth, ConstHeight)
@@ -43,13 +94,20 @@ func NewLandingModel() LandingModel {
 	themeInput := textinput.New()
 	themeInput.Placeholder = "Theme Name"
 
+	importInput := textinput.New()
+	importInput.Placeholder = "Blank for $LS_COLORS"
+	importInput.Width = ConstWidth - 6
+
 	newHelp := help.New()
 	newHelp.ShowAll = true
 	newHelp.Width = ConstWidth - 2
 
 	return LandingModel{
-		ThemeList:  l,
-		ThemeInput: themeInput,
+		ThemeList:   l,
+		ThemeInput:  themeInput,
+		ImportInput: importInput,
+
+		err: err,
 
 		keys: newLandingKeymap(),
 		help: newHelp,
@@ -64,54 +122,110 @@ func (m LandingModel) Init() tea.Cmd {
 func (m LandingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.err = nil
+		// Keys are typed into the filter while the list is being filtered
+		if m.ThemeList.FilterState() == list.Filtering {
+			break
+		}
+		selected, hasSelected := m.ThemeList.SelectedItem().(themeItem)
 		switch msg.String() {
 		case "enter":
-			if m.InputActive {
+			if m.importActive {
+				data, err := theme.ReadImportSource(m.ImportInput.Value())
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.importData = data
+				m.importActive = false
+				m.ImportInput.Blur()
+				m.InputActive = true
+				m.isImporting = true
+				return m, m.ThemeInput.Focus()
+			} else if m.InputActive {
 				name := m.ThemeInput.Value()
+				if m.isImporting {
+					t, err := theme.Import(name, m.importData)
+					if err != nil {
+						m.err = err
+						return m, nil
+					}
+					m.isImporting = false
+					m.importData = ""
+					m.ThemeInput.Blur()
+					return NewThemeModel(t), nil
+				}
+				if m.isExtending {
+					t, err := theme.Extend(name, m.themeToExtend)
+					m.isExtending = false
+					m.themeToExtend = ""
+					if err != nil {
+						m.err = err
+						return m, nil
+					}
+					m.ThemeInput.Blur()
+					return NewThemeModel(t), nil
+				}
 				if m.isCopying {
-					srcDir := filepath.Join(ThemeConfigFolder, m.themeToCopy)
-					destDir := filepath.Join(ThemeConfigFolder, name)
+					srcDir := filepath.Join(theme.ThemeConfigFolder, m.themeToCopy)
+					destDir := filepath.Join(theme.ThemeConfigFolder, name)
 
 					err := os.CopyFS(destDir, os.DirFS(srcDir))
-					if err != nil {
-						log.Fatal(err)
-					}
 					m.isCopying = false
 					m.themeToCopy = ""
+					if err != nil {
+						m.err = fmt.Errorf("copying theme to %q: %w", name, err)
+						return m, nil
+					}
+				}
+				t, err := theme.GetTheme(name)
+				if err != nil {
+					m.err = err
+					return m, nil
 				}
 				m.ThemeInput.Blur()
-				return NewThemeModel(GetTheme(name)), nil
+				return NewThemeModel(t), nil
 
-			} else {
-				selected := m.ThemeList.SelectedItem().(Theme)
-				return NewThemeModel(selected), nil
+			} else if hasSelected {
+				return NewThemeModel(selected.Theme), nil
+			}
+		case "i":
+			if !m.InputActive && !m.DeleteActive && !m.importActive {
+				m.importActive = true
+				m.ImportInput.SetValue("")
+				return m, m.ImportInput.Focus()
 			}
 		case "d":
-			if !m.InputActive && !m.DeleteActive {
+			if !m.InputActive && !m.DeleteActive && !m.importActive {
 				m.DeleteActive = true
 			}
 		case "y":
 			if m.DeleteActive {
-				selected := m.ThemeList.SelectedItem().(Theme)
-				m.deleteTheme(selected.Name)
 				m.DeleteActive = false
-				m.ThemeList.RemoveItem(m.ThemeList.Index())
+				if !hasSelected {
+					return m, nil
+				}
+				m.err = m.deleteTheme(selected.Name)
+				if m.err == nil {
+					m.ThemeList.RemoveItem(m.ThemeList.Index())
+				}
 			}
 
 		case "g":
-			selected := m.ThemeList.SelectedItem().(Theme)
-			err := selected.GenerateDirColors()
-			if err != nil {
-				log.Fatal(err)
-			}
+			if !m.InputActive && !m.importActive && hasSelected {
+				m.err = selected.GenerateDirColors()
 
-			return m, nil
-		case "n", "c":
-			if !m.InputActive && !m.DeleteActive {
+				return m, nil
+			}
+		case "n", "c", "e":
+			if !m.InputActive && !m.DeleteActive && !m.importActive && (msg.String() == "n" || hasSelected) {
 				m.InputActive = true
 				if msg.String() == "c" {
 					m.isCopying = true
-					m.themeToCopy = m.ThemeList.SelectedItem().(Theme).Name
+					m.themeToCopy = selected.Name
+				} else if msg.String() == "e" {
+					m.isExtending = true
+					m.themeToExtend = selected.Name
 				}
 				return m, m.ThemeInput.Focus()
 			}
@@ -119,8 +233,14 @@ func (m LandingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.DeleteActive = false
 			}
 		case "esc":
-			if m.InputActive {
+			if m.importActive {
+				m.importActive = false
+				m.ImportInput.Blur()
+			} else if m.InputActive {
 				m.InputActive = false
+				m.isImporting = false
+				m.importData = ""
+				m.isCopying, m.isExtending = false, false
 				m.ThemeInput.Blur()
 				m.ThemeInput.SetValue("")
 			} else {
@@ -129,7 +249,9 @@ func (m LandingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 	var cmd tea.Cmd
-	if m.InputActive {
+	if m.importActive {
+		m.ImportInput, cmd = m.ImportInput.Update(msg)
+	} else if m.InputActive {
 		m.ThemeInput, cmd = m.ThemeInput.Update(msg)
 	} else {
 		m.ThemeList, cmd = m.ThemeList.Update(msg)
@@ -138,28 +260,37 @@ func (m LandingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m LandingModel) View() string {
-	if m.InputActive {
-		return RenderModel(Center(fmt.Sprintf("%v\n%v", TitleStyle.Render("New Theme Name"), m.ThemeInput.View())), "")
+	if m.importActive {
+		return RenderModel(Center(fmt.Sprintf("%v\n%v\n\n%v", TitleStyle.Render("Import dircolors / LS_COLORS"),
+			SubtitleStyle.Render("Path to file"), m.ImportInput.View())), "", m.err)
+	} else if m.InputActive {
+		title := TitleStyle.Render("New Theme Name")
+		if m.isExtending {
+			title += "\n" + SubtitleStyle.Render("Extending "+m.themeToExtend)
+		}
+		return RenderModel(Center(fmt.Sprintf("%v\n%v", title, m.ThemeInput.View())), "", m.err)
 	} else if m.DeleteActive {
-		return RenderModel(Center(TitleStyle.Render("Delete this theme? (y/n)")), "")
+		return RenderModel(Center(TitleStyle.Render("Delete this theme? (y/n)")), "", m.err)
 	} else {
-		listHeader := CenterHorz(TitleStyle.Render("Current Themes") + "\n" + SubtitleStyle.Render(ThemeConfigFolder))
-		return RenderModel(listHeader+"\n"+m.ThemeList.View(), m.help.View(m.keys))
+		listHeader := CenterHorz(TitleStyle.Render("Current Themes") + "\n" + SubtitleStyle.Render(theme.ThemeConfigFolder))
+		return RenderModel(listHeader+"\n"+m.ThemeList.View(), m.help.View(m.keys), m.err)
 	}
 }
 
-func (m *LandingModel) createTheme(name string) {
-	path := filepath.Join(ThemeConfigFolder, name)
+func (m *LandingModel) createTheme(name string) error {
+	path := filepath.Join(theme.ThemeConfigFolder, name)
 	if err := os.MkdirAll(path, 0755); err != nil {
-		fmt.Printf("Error creating theme folder: %v\n", err)
+		return fmt.Errorf("creating theme folder %q: %w", name, err)
 	}
+	return nil
 }
 
-func (m *LandingModel) deleteTheme(name string) {
-	path := filepath.Join(ThemeConfigFolder, name)
+func (m *LandingModel) deleteTheme(name string) error {
+	path := filepath.Join(theme.ThemeConfigFolder, name)
 	if err := os.RemoveAll(path); err != nil {
-		fmt.Printf("Error deleting theme folder: %v\n", err)
+		return fmt.Errorf("deleting theme folder %q: %w", name, err)
 	}
+	return nil
 }
 
 type landingKeymap struct {
@@ -171,7 +302,9 @@ type landingKeymap struct {
 	Delete key.Binding
 	New    key.Binding
 	Copy   key.Binding
+	Extend key.Binding
 	Filter key.Binding
+	Import key.Binding
 }
 
 func (k landingKeymap) ShortHelp() []key.Binding {
@@ -181,7 +314,7 @@ func (k landingKeymap) ShortHelp() []key.Binding {
 func (k landingKeymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Quit, k.Select},
-		{k.New, k.Delete, k.Copy, k.Filter},
+		{k.New, k.Delete, k.Copy, k.Extend, k.Filter, k.Import},
 	}
 }
 
@@ -215,9 +348,17 @@ func newLandingKeymap() landingKeymap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "Copy Theme"),
 		),
+		Extend: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "Extend Theme"),
+		),
 		Filter: key.NewBinding(
 			key.WithKeys("/"),
 			key.WithHelp("/", "Filter"),
 		),
+		Import: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "Import"),
+		),
 	}
 }
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"go.dalton.dog/stylish/theme"
+)
+
+// TestLandingWithoutThemes sends every key that acts on the selected theme to an empty list,
+// which shouldn't panic or open an input
+func TestLandingWithoutThemes(t *testing.T) {
+	folder := theme.ThemeConfigFolder
+	theme.ThemeConfigFolder = t.TempDir()
+	t.Cleanup(func() { theme.ThemeConfigFolder = folder })
+
+	var m tea.Model = NewLandingModel()
+	for _, key := range []string{"y", "g", "c", "e", "d", "y"} {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		_ = m.View()
+	}
+
+	landing := m.(LandingModel)
+	if landing.err != nil {
+		t.Errorf("err = %v, want nil", landing.err)
+	}
+	if landing.InputActive || landing.DeleteActive {
+		t.Error("an input was left open without a theme to act on")
+	}
+}
+
+// TestLandingFiltering types shortcut keys into the filter, which shouldn't trigger them
+func TestLandingFiltering(t *testing.T) {
+	folder := theme.ThemeConfigFolder
+	theme.ThemeConfigFolder = t.TempDir()
+	t.Cleanup(func() { theme.ThemeConfigFolder = folder })
+	if err := os.Mkdir(filepath.Join(theme.ThemeConfigFolder, "dusk"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var m tea.Model = NewLandingModel()
+	for _, key := range []string{"/", "i", "e", "n", "c", "d", "g"} {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	landing := m.(LandingModel)
+	if landing.InputActive || landing.DeleteActive || landing.importActive {
+		t.Error("a shortcut fired while typing a filter")
+	}
+	if _, err := os.Stat(filepath.Join(theme.ThemeConfigFolder, "dusk", ".dircolors")); !os.IsNotExist(err) {
+		t.Errorf("'g' generated dircolors while filtering (stat error: %v)", err)
+	}
+	if got := landing.ThemeList.FilterValue(); got != "iencdg" {
+		t.Errorf("filter = %q, want %q", got, "iencdg")
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"io"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -21,12 +20,6 @@ import (
 var DefaultTheme embed.FS
 
 func main() {
-	// Check that `dircolors` is installed
-	checkCommand("dircolors")
-
-	// Check that `ls` is installed
-	checkCommand("ls")
-
 	// Check that stylish dir exists, creating it and copying the default theme into it if needed
 	checkConfigDir()
 
@@ -34,13 +27,6 @@ func main() {
 	cmd.Execute()
 }
 
-func checkCommand(command string) {
-	_, err := exec.LookPath(command)
-	if err != nil {
-		log.Fatal("Package " + command + " not found on PATH. Please install it before continuing.")
-	}
-}
-
 func checkConfigDir() {
 	userCfgDir, err := os.UserConfigDir()
 	if err != nil {
@@ -0,0 +1,144 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// bsdSlots are the LS_COLORS codes filling each of the 11 LSCOLORS positions, in order.
+// When a position lists more than one code, the first one the theme styles is used, falling
+// back the same way GNU ls does for directories. LSCOLORS has no position for sticky
+// directories that aren't writable to others, which BSD ls colors as directories.
+var bsdSlots = [][]string{
+	{"di"},             // directory
+	{"ln"},             // symbolic link
+	{"so"},             // socket
+	{"pi"},             // pipe
+	{"ex"},             // executable
+	{"bd"},             // block special
+	{"cd"},             // character special
+	{"su"},             // executable with setuid bit set
+	{"sg"},             // executable with setgid bit set
+	{"tw", "ow", "di"}, // directory writable to others, with sticky bit
+	{"ow", "di"},       // directory writable to others, without sticky bit
+}
+
+// bsdPalette is the 8 colors LSCOLORS can pick from, in letter order starting at `a`
+var bsdPalette = []string{"000000", "800000", "008000", "808000", "000080", "800080", "008080", "C0C0C0"}
+
+// BSDColors converts the theme into the value expected by the BSD/macOS LSCOLORS environment
+// variable. Each hex color is swapped for the nearest of the 8 BSD colors, and bold is the
+// only attribute carried over. Every filetype that can't be represented is returned as a warning.
+func (t Theme) BSDColors() (string, []string) {
+	styled := make(map[string]Style)
+	var warnings []string
+
+	for _, style := range t.Styles {
+		var dropped []string
+		used := false
+		for _, fileType := range style.FileTypes {
+			if IsEzaKey(fileType) {
+				continue
+			}
+
+			code, err := Key(fileType)
+			if err != nil || code == "" || !isBSDCode(code) {
+				if strings.TrimSpace(fileType) != "" {
+					dropped = append(dropped, fileType)
+				}
+				continue
+			}
+			styled[code] = style
+			used = true
+		}
+
+		if len(dropped) > 0 {
+			warnings = append(warnings, fmt.Sprintf("style %q: %v can't be represented in LSCOLORS", style.Name, strings.Join(dropped, ", ")))
+		}
+		if attrs := bsdDropped(style); used && len(attrs) > 0 {
+			warnings = append(warnings, fmt.Sprintf("style %q: LSCOLORS only supports bold, so %v is left out", style.Name, strings.Join(attrs, ", ")))
+		}
+	}
+
+	var out strings.Builder
+	for _, slot := range bsdSlots {
+		fore, back := "x", "x"
+		for _, code := range slot {
+			if style, ok := styled[code]; ok {
+				fore = bsdLetter(style.ResolvedFore(), style.Bold)
+				back = bsdLetter(style.ResolvedBack(), false)
+				break
+			}
+		}
+		out.WriteString(fore + back)
+	}
+
+	return out.String(), warnings
+}
+
+// bsdDropped lists the style's attributes that LSCOLORS has no way to show
+func bsdDropped(style Style) []string {
+	attrs := []struct {
+		on   bool
+		name string
+	}{
+		{style.Dim, "dim"},
+		{style.Italic, "italic"},
+		{style.Under, "underline"},
+		{style.Blink, "blink"},
+		{style.Reverse, "reverse"},
+		{style.Hidden, "hidden"},
+		{style.Strike, "strikethrough"},
+		{style.Overline, "overline"},
+	}
+
+	var dropped []string
+	for _, attr := range attrs {
+		if attr.on {
+			dropped = append(dropped, attr.name)
+		}
+	}
+	return dropped
+}
+
+// isBSDCode reports whether an LS_COLORS code has a position in LSCOLORS
+func isBSDCode(code string) bool {
+	for _, slot := range bsdSlots {
+		for _, slotCode := range slot {
+			if slotCode == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bsdLetter picks the LSCOLORS letter for the BSD color nearest to hex, or `x` for the terminal's
+// default. Bold colors are uppercase.
+func bsdLetter(hex string, bold bool) string {
+	target, err := colorful.Hex("#" + strings.TrimPrefix(hex, "#"))
+	if hex == "" || err != nil {
+		if bold {
+			return "X"
+		}
+		return "x"
+	}
+
+	nearest := 0
+	nearestDist := -1.0
+	for i, candidate := range bsdPalette {
+		color, _ := colorful.Hex("#" + candidate)
+		dist := target.DistanceLab(color)
+		if nearestDist < 0 || dist < nearestDist {
+			nearest, nearestDist = i, dist
+		}
+	}
+
+	letter := string(rune('a' + nearest))
+	if bold {
+		letter = strings.ToUpper(letter)
+	}
+	return letter
+}
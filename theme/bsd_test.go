@@ -0,0 +1,84 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBSDLetter(t *testing.T) {
+	tests := []struct {
+		hex  string
+		bold bool
+		want string
+	}{
+		{"", false, "x"},
+		{"", true, "X"},
+		{"nothex", false, "x"},
+		{"000000", false, "a"},
+		{"800000", false, "b"},
+		{"FF0000", false, "b"},
+		{"00FF00", true, "C"},
+		{"#808000", false, "d"},
+		{"000080", false, "e"},
+		{"800080", true, "F"},
+		{"008080", false, "g"},
+		{"FFFFFF", false, "h"},
+	}
+
+	for _, test := range tests {
+		if got := bsdLetter(test.hex, test.bold); got != test.want {
+			t.Errorf("bsdLetter(%q, %v) = %q, want %q", test.hex, test.bold, got, test.want)
+		}
+	}
+}
+
+func TestBSDColors(t *testing.T) {
+	tests := []struct {
+		name     string
+		styles   []Style
+		want     string
+		warnings int
+	}{
+		{
+			name: "empty",
+			want: strings.Repeat("xx", 11),
+		},
+		{
+			name:   "directory and link",
+			styles: []Style{{Name: "Dirs", Fore: "000080", Bold: true, FileTypes: []string{"DIR"}}, {Name: "Links", Fore: "008080", Back: "000000", FileTypes: []string{"LINK"}}},
+			want:   "Exga" + strings.Repeat("xx", 7) + "ExEx",
+		},
+		{
+			name:   "bold without a color",
+			styles: []Style{{Name: "Exec", Bold: true, FileTypes: []string{"EXEC"}}},
+			want:   strings.Repeat("xx", 4) + "Xx" + strings.Repeat("xx", 6),
+		},
+		{
+			name:   "other writable falls back to directories",
+			styles: []Style{{Name: "Dirs", Fore: "000080", FileTypes: []string{"DIR"}}},
+			want:   "ex" + strings.Repeat("xx", 8) + "exex",
+		},
+		{
+			name:   "sticky other writable falls back to other writable",
+			styles: []Style{{Name: "Dirs", Fore: "000080", FileTypes: []string{"DIR"}}, {Name: "Open", Fore: "008000", FileTypes: []string{"OTHER_WRITABLE"}}},
+			want:   "ex" + strings.Repeat("xx", 8) + "cxcx",
+		},
+		{
+			name:     "unsupported filetypes and attributes",
+			styles:   []Style{{Name: "Mixed", Fore: "800000", Italic: true, Under: true, FileTypes: []string{"PIPE", ".go"}}},
+			want:     strings.Repeat("xx", 3) + "bx" + strings.Repeat("xx", 7),
+			warnings: 2,
+		},
+	}
+
+	for _, test := range tests {
+		th := Theme{Name: "test", Styles: test.styles}
+		got, warnings := th.BSDColors()
+		if got != test.want {
+			t.Errorf("%v: BSDColors() = %q, want %q", test.name, got, test.want)
+		}
+		if len(warnings) != test.warnings {
+			t.Errorf("%v: got warnings %q, want %v", test.name, strings.Join(warnings, "; "), test.warnings)
+		}
+	}
+}
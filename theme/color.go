@@ -0,0 +1,51 @@
+package theme
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// HexCodePattern will regex match a 6 digit hexcode, and nothing else
+const HexCodePattern = "^[0-9a-fA-F]{6}$"
+
+func ValidHexCode(input string) error {
+	match, err := regexp.MatchString(HexCodePattern, input)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return errors.New("Enter a valid hex code")
+	}
+
+	return nil
+
+}
+
+func HexToRGB(hex string) termenv.RGBColor {
+	if strings.HasPrefix(hex, "#") {
+		return termenv.RGBColor(hex)
+	} else {
+		return termenv.RGBColor("#" + hex)
+	}
+
+}
+
+func HexToEightBit(hex string) termenv.Color {
+	prof256 := termenv.ANSI256
+	return prof256.Convert(HexToRGB(hex))
+}
+
+// WithProfile returns a copy of the theme whose colors are encoded for the given terminal
+// color profile, from truecolor down to none at all. Truecolor is used until one is picked.
+func (t Theme) WithProfile(profile termenv.Profile) Theme {
+	styles := make([]Style, len(t.Styles))
+	for i, style := range t.Styles {
+		style.profile = profile
+		styles[i] = style
+	}
+	t.Styles = styles
+	return t
+}
@@ -0,0 +1,208 @@
+package theme
+
+import (
+	"math"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// MinContrastRatio is the WCAG 2 AA contrast ratio for normal sized text
+const MinContrastRatio = 4.5
+
+// MinAPCA is the APCA lightness contrast (Lc) recommended for content text that isn't body text
+const MinAPCA = 60.0
+
+// Contrast measures how readable a style's foreground is against its background
+type Contrast struct {
+	// Fore and Back are the hex codes that were compared, after filling in the terminal's defaults
+	Fore, Back string
+
+	Ratio float64 // WCAG 2 contrast ratio, from 1 to 21
+	Lc    float64 // APCA lightness contrast, negative for light text on a dark background
+}
+
+// Pass reports whether the contrast meets both the WCAG AA ratio and the APCA minimum
+func (c Contrast) Pass() bool {
+	return c.Ratio >= MinContrastRatio && math.Abs(c.Lc) >= MinAPCA
+}
+
+// ContrastRatio is the WCAG 2 contrast ratio between two hex codes
+func ContrastRatio(fore, back string) float64 {
+	l1, l2 := relativeLuminance(hexColor(fore)), relativeLuminance(hexColor(back))
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// relativeLuminance is the WCAG 2 luminance of a color, from its linearized sRGB channels
+func relativeLuminance(c colorful.Color) float64 {
+	r, g, b := c.LinearRgb()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// APCAContrast is the APCA (0.0.98G-4g) lightness contrast of fore text on a back background.
+// Dark text on a light background is positive, and light text on a dark background negative.
+func APCAContrast(fore, back string) float64 {
+	const (
+		normBG, normTXT = 0.56, 0.57
+		revBG, revTXT   = 0.65, 0.62
+		blkThrs         = 0.022
+		blkClmp         = 1.414
+		scale           = 1.14
+		loOffset        = 0.027
+		loClip          = 0.1
+		deltaYmin       = 0.0005
+	)
+
+	screenLuminance := func(c colorful.Color) float64 {
+		y := 0.2126729*math.Pow(c.R, 2.4) + 0.7151522*math.Pow(c.G, 2.4) + 0.0721750*math.Pow(c.B, 2.4)
+		// Soft clamp near black, where screens and eyes both stop telling colors apart
+		if y < blkThrs {
+			y += math.Pow(blkThrs-y, blkClmp)
+		}
+		return y
+	}
+
+	txt, bg := screenLuminance(hexColor(fore)), screenLuminance(hexColor(back))
+	if math.Abs(bg-txt) < deltaYmin {
+		return 0
+	}
+
+	var lc float64
+	if bg > txt {
+		sapc := (math.Pow(bg, normBG) - math.Pow(txt, normTXT)) * scale
+		if sapc >= loClip {
+			lc = sapc - loOffset
+		}
+	} else {
+		sapc := (math.Pow(bg, revBG) - math.Pow(txt, revTXT)) * scale
+		if sapc <= -loClip {
+			lc = sapc + loOffset
+		}
+	}
+	return lc * 100
+}
+
+// DefaultColors are the terminal foreground and background the theme is checked against on the
+// given background. The manifest's colors are used for the background the theme was designed
+// for, otherwise plain white and black stand in.
+func (t Theme) DefaultColors(background string) (fore, back string) {
+	designed := t.Manifest.Background
+	if designed == "" {
+		designed = BackgroundDark
+	}
+	if background == "" {
+		background = designed
+	}
+
+	fore, back = "FFFFFF", "000000"
+	if background == BackgroundLight {
+		fore, back = back, fore
+	}
+
+	if background == designed {
+		if ValidHexCode(t.Manifest.Fore) == nil {
+			fore = t.Manifest.Fore
+		}
+		if ValidHexCode(t.Manifest.Back) == nil {
+			back = t.Manifest.Back
+		}
+	}
+	return fore, back
+}
+
+// Contrast measures the style's colors against each other, filling in whichever one it leaves
+// to the terminal with defaultFore or defaultBack. Styles that don't set any colors, or whose
+// colors can't be resolved, have nothing to measure.
+func (s Style) Contrast(defaultFore, defaultBack string) (Contrast, bool) {
+	fore, back := s.ResolvedFore(), s.ResolvedBack()
+	if fore == "" && back == "" {
+		return Contrast{}, false
+	}
+	if fore == "" {
+		fore = defaultFore
+	}
+	if back == "" {
+		back = defaultBack
+	}
+	if s.Reverse {
+		fore, back = back, fore
+	}
+	if ValidHexCode(fore) != nil || ValidHexCode(back) != nil {
+		return Contrast{}, false
+	}
+
+	return Contrast{
+		Fore:  strings.ToUpper(fore),
+		Back:  strings.ToUpper(back),
+		Ratio: ContrastRatio(fore, back),
+		Lc:    APCAContrast(fore, back),
+	}, true
+}
+
+// SuggestFore finds the color closest to fore that's readable on back, by only changing its
+// lightness so it keeps the same hue. Returns fore unchanged if it already passes.
+func SuggestFore(fore, back string) string {
+	return readableShade(fore, back, func(hex string) bool {
+		return Contrast{Ratio: ContrastRatio(hex, back), Lc: APCAContrast(hex, back)}.Pass()
+	})
+}
+
+// SuggestBack finds the color closest to back that fore is readable on, the same way as SuggestFore
+func SuggestBack(back, fore string) string {
+	return readableShade(back, fore, func(hex string) bool {
+		return Contrast{Ratio: ContrastRatio(fore, hex), Lc: APCAContrast(fore, hex)}.Pass()
+	})
+}
+
+// SuggestFore suggests a new value for the style's fore that makes c pass. Reversed styles show
+// their fore as the background, so it's suggested as a background for the shown text instead.
+func (s Style) SuggestFore(c Contrast) string {
+	if s.Reverse {
+		return SuggestBack(c.Back, c.Fore)
+	}
+	return SuggestFore(c.Fore, c.Back)
+}
+
+// readableShade walks the lightness of hex both ways until passes accepts it, keeping whichever
+// passing shade looks closest to the original. other is the color it's measured against.
+func readableShade(hex, other string, passes func(hex string) bool) string {
+	if passes(hex) {
+		return strings.ToUpper(hex)
+	}
+
+	original := hexColor(hex)
+	h, c, l := original.Hcl()
+
+	best, bestDistance := "", math.Inf(1)
+	for _, step := range []float64{0.005, -0.005} {
+		for candidateL := l + step; candidateL >= 0 && candidateL <= 1; candidateL += step {
+			candidate := colorful.Hcl(h, c, candidateL).Clamped()
+			hex := strings.ToUpper(strings.TrimPrefix(candidate.Hex(), "#"))
+			if !passes(hex) {
+				continue
+			}
+			if distance := original.DistanceCIEDE2000(candidate); distance < bestDistance {
+				best, bestDistance = hex, distance
+			}
+			break
+		}
+	}
+
+	// Mid-tone colors to measure against can leave no shade of the hue readable, so fall back to black or white
+	if best == "" {
+		best = "000000"
+		if ContrastRatio("FFFFFF", other) > ContrastRatio("000000", other) {
+			best = "FFFFFF"
+		}
+	}
+	return best
+}
+
+// hexColor parses a hex code with or without its leading '#'. Invalid codes come out black.
+func hexColor(hex string) colorful.Color {
+	color, _ := colorful.Hex("#" + strings.TrimPrefix(hex, "#"))
+	return color
+}
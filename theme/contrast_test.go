@@ -0,0 +1,94 @@
+package theme
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		fore, back string
+		want       float64
+	}{
+		{"000000", "FFFFFF", 21},
+		{"FFFFFF", "000000", 21},
+		{"FFFFFF", "FFFFFF", 1},
+		{"767676", "FFFFFF", 4.54},
+		{"777777", "FFFFFF", 4.48},
+		{"FF0000", "FFFFFF", 4.00},
+		{"0000FF", "000000", 2.44},
+	}
+
+	for _, test := range tests {
+		if got := ContrastRatio(test.fore, test.back); math.Abs(got-test.want) > 0.01 {
+			t.Errorf("ContrastRatio(%v, %v) = %.3f, want %.2f", test.fore, test.back, got, test.want)
+		}
+	}
+}
+
+// The expected values come from the reference APCA implementation, apca-w3 0.0.98G-4g
+func TestAPCAContrast(t *testing.T) {
+	tests := []struct {
+		fore, back string
+		want       float64
+	}{
+		{"000000", "FFFFFF", 106.04067321268862},
+		{"FFFFFF", "000000", -107.88473318309848},
+		{"888888", "FFFFFF", 63.056469930209424},
+		{"FFFFFF", "888888", -68.54146436644962},
+		{"000000", "AAAAAA", 58.146262578561334},
+		{"AAAAAA", "000000", -56.24113336839742},
+		{"777777", "777777", 0},
+	}
+
+	for _, test := range tests {
+		if got := APCAContrast(test.fore, test.back); math.Abs(got-test.want) > 0.001 {
+			t.Errorf("APCAContrast(%v, %v) = %v, want %v", test.fore, test.back, got, test.want)
+		}
+	}
+}
+
+func TestSuggestFore(t *testing.T) {
+	tests := []struct{ fore, back string }{
+		{"777777", "FFFFFF"},
+		{"FF0000", "FFFFFF"},
+		{"0000FF", "000000"},
+		{"333333", "222222"},
+	}
+
+	for _, test := range tests {
+		suggested := SuggestFore(test.fore, test.back)
+		contrast := Contrast{Ratio: ContrastRatio(suggested, test.back), Lc: APCAContrast(suggested, test.back)}
+		if !contrast.Pass() {
+			t.Errorf("SuggestFore(%v, %v) = %v, which doesn't pass (ratio %.2f, Lc %.1f)", test.fore, test.back, suggested, contrast.Ratio, contrast.Lc)
+		}
+	}
+}
+
+func TestStyleSuggestFore(t *testing.T) {
+	tests := []struct {
+		fore, back string
+		reverse    bool
+	}{
+		{"777777", "FFFFFF", false},
+		{"777777", "FFFFFF", true},
+		{"0000FF", "000000", false},
+		{"0000FF", "000000", true},
+		{"", "333333", true},
+	}
+
+	for _, test := range tests {
+		style := Style{Fore: test.fore, Back: test.back, Reverse: test.reverse}
+		contrast, ok := style.Contrast("DDDDDD", "111111")
+		if !ok {
+			t.Fatalf("%+v: nothing to measure", test)
+		}
+
+		// The suggestion replaces the style's fore, then goes through Reverse again
+		style.Fore = style.SuggestFore(contrast)
+		if contrast, _ := style.Contrast("DDDDDD", "111111"); !contrast.Pass() {
+			t.Errorf("%+v: suggested fore %v shows #%v on #%v, which doesn't pass (ratio %.2f, Lc %.1f)",
+				test, style.Fore, contrast.Fore, contrast.Back, contrast.Ratio, contrast.Lc)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// DownsampledFore is the style's foreground as it's encoded for the given color profile, using
+// the pinned 256 or 16 color in place of the nearest match when there is one.
+// Returns nil if the style doesn't set a valid foreground.
+func (s Style) DownsampledFore(profile termenv.Profile) termenv.Color {
+	return downsample(s.ResolvedFore(), s.Fore256, s.Fore16, profile, s.matcher)
+}
+
+// DownsampledBack is the style's background as it's encoded for the given color profile, using
+// the pinned 256 or 16 color in place of the nearest match when there is one.
+// Returns nil if the style doesn't set a valid background.
+func (s Style) DownsampledBack(profile termenv.Profile) termenv.Color {
+	return downsample(s.ResolvedBack(), s.Back256, s.Back16, profile, s.matcher)
+}
+
+// downsample converts a hex code for the given profile. Without a 16 color pin, 16 color
+// terminals get the nearest basic color to the 256 color pin, or to the matcher's pick, instead.
+func downsample(hex string, pin256, pin16 *int, profile termenv.Profile, matcher Matcher) termenv.Color {
+	if ValidHexCode(hex) != nil {
+		return nil
+	}
+
+	switch {
+	case profile == termenv.ANSI256 && validPin(pin256, 256):
+		return termenv.ANSI256Color(*pin256)
+	case profile == termenv.ANSI && validPin(pin16, 16):
+		return termenv.ANSIColor(*pin16)
+	case profile == termenv.ANSI && validPin(pin256, 256):
+		return profile.Convert(termenv.ANSI256Color(*pin256))
+	case (profile == termenv.ANSI256 || profile == termenv.ANSI) && matcher != "" && matcher != MatchRGB:
+		return profile.Convert(matcher.Nearest256(hex))
+	}
+	return profile.Convert(HexToRGB(hex))
+}
+
+// validPin reports whether a pinned color is set and one of the first n colors
+func validPin(pin *int, n int) bool {
+	return pin != nil && *pin >= 0 && *pin < n
+}
+
+func clonePin(pin *int) *int {
+	if pin == nil {
+		return nil
+	}
+	value := *pin
+	return &value
+}
+
+// lintDownsampled checks the styles' pinned colors, and finds styles whose colors differ, but
+// come out the same on a 256 color terminal
+func (t Theme) lintDownsampled() []Problem {
+	var problems []Problem
+
+	// The styles with each 256 color encoding, and how many different truecolor encodings they have
+	styles := make(map[string][]string)
+	truecolors := make(map[string]map[string]bool)
+	var encodings []string
+
+	for _, style := range t.Styles {
+		pins := []struct {
+			name string
+			pin  *int
+			n    int
+		}{
+			{"fore256", style.Fore256, 256},
+			{"back256", style.Back256, 256},
+			{"fore16", style.Fore16, 16},
+			{"back16", style.Back16, 16},
+		}
+		for _, pin := range pins {
+			if pin.pin != nil && !validPin(pin.pin, pin.n) {
+				problems = append(problems, Problem{style.Name, fmt.Sprintf("%v %v should be between 0 and %v", pin.name, *pin.pin, pin.n-1)})
+			}
+		}
+
+		truecolor := colorsSequence(style, termenv.TrueColor)
+		if truecolor == "" {
+			continue
+		}
+		encoding := colorsSequence(style, termenv.ANSI256)
+		if truecolors[encoding] == nil {
+			truecolors[encoding] = make(map[string]bool)
+			encodings = append(encodings, encoding)
+		}
+		truecolors[encoding][truecolor] = true
+		styles[encoding] = append(styles[encoding], fmt.Sprintf("%q", style.Name))
+	}
+
+	for _, encoding := range encodings {
+		if len(truecolors[encoding]) < 2 {
+			continue
+		}
+		problems = append(problems, Problem{"", fmt.Sprintf("%v have different colors, but look the same on 256 color terminals (%v). Pin fore256 or back256 to tell them apart",
+			strings.Join(styles[encoding], ", "), encoding)})
+	}
+
+	return problems
+}
+
+// colorsSequence is the SGR parameters for just the style's colors, encoded for the given profile
+func colorsSequence(style Style, profile termenv.Profile) string {
+	var codes []string
+	if fore := style.DownsampledFore(profile); fore != nil {
+		codes = append(codes, fore.Sequence(false))
+	}
+	if back := style.DownsampledBack(profile); back != nil {
+		codes = append(codes, back.Sequence(true))
+	}
+	return strings.Join(codes, ";")
+}
@@ -0,0 +1,127 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EzaPrefix marks a filetype entry as one of eza's own UI elements rather than a file
+const EzaPrefix = "eza:"
+
+// EzaKeys maps each key eza understands in EZA_COLORS, beyond the LS_COLORS ones, to what it colors
+var EzaKeys = map[string]string{
+	"ur": "User +r bit",
+	"uw": "User +w bit",
+	"ux": "User +x bit (files)",
+	"ue": "User +x bit (other types)",
+	"gr": "Group +r bit",
+	"gw": "Group +w bit",
+	"gx": "Group +x bit",
+	"tr": "Others +r bit",
+	"tw": "Others +w bit",
+	"tx": "Others +x bit",
+	"su": "Setuid, setgid, and sticky bits (files)",
+	"sf": "Setuid, setgid, and sticky bits (other types)",
+	"xa": "Extended attribute marker",
+	"oc": "Octal permissions",
+	"ff": "BSD file flags",
+
+	"sn": "File size number",
+	"sb": "File size unit",
+	"nb": "Size number, bytes",
+	"nk": "Size number, kilobytes",
+	"nm": "Size number, megabytes",
+	"ng": "Size number, gigabytes",
+	"nt": "Size number, huge",
+	"ub": "Size unit, bytes",
+	"uk": "Size unit, kilobytes",
+	"um": "Size unit, megabytes",
+	"ug": "Size unit, gigabytes",
+	"ut": "Size unit, huge",
+	"df": "Device major ID",
+	"ds": "Device minor ID",
+
+	"uu": "User that is you",
+	"uR": "User that is root",
+	"un": "User that is someone else",
+	"gu": "Group you belong to",
+	"gR": "Group that is root",
+	"gn": "Group you aren't in",
+
+	"lc": "Number of links",
+	"lm": "Link count on multi-link files",
+	"lp": "Symlink path",
+
+	"ga": "Git: new",
+	"gm": "Git: modified",
+	"gd": "Git: deleted",
+	"gv": "Git: renamed",
+	"gt": "Git: type change",
+	"gi": "Git: ignored",
+	"gc": "Git: conflicted",
+	"Gm": "Git repo: main branch",
+	"Go": "Git repo: other branch",
+	"Gc": "Git repo: clean",
+	"Gd": "Git repo: dirty",
+
+	"xx": "Punctuation",
+	"da": "Timestamp",
+	"in": "Inode number",
+	"bl": "Number of blocks",
+	"hd": "Table header row",
+	"cc": "Control characters",
+	"bO": "Overlay style for broken symlink paths",
+	"sp": "Special (not file, dir, or link)",
+	"mp": "Mount point",
+
+	"im": "Images",
+	"vi": "Videos",
+	"mu": "Lossy music",
+	"lo": "Lossless music",
+	"cr": "Cryptographic files",
+	"do": "Documents",
+	"co": "Compressed files",
+	"tm": "Temporary files",
+	"cm": "Compilation artifacts",
+	"bu": "Build files",
+	"sc": "Source code",
+	"ic": "Icons",
+
+	"Sn": "No security context",
+	"Su": "SELinux user",
+	"Sr": "SELinux role",
+	"St": "SELinux type",
+	"Sl": "SELinux level",
+}
+
+// IsEzaKey reports whether a filetype entry refers to an eza UI element
+func IsEzaKey(fileType string) bool {
+	return strings.HasPrefix(strings.TrimSpace(fileType), EzaPrefix)
+}
+
+// EzaColors converts the theme's eza UI entries (ex: `eza:ur`) into the value expected
+// by the EZA_COLORS environment variable. Filetypes are left to LS_COLORS, which eza also reads.
+func (t Theme) EzaColors() (string, error) {
+	var out strings.Builder
+
+	for _, style := range t.Styles {
+		seq := style.Sequence()
+		if seq == "" {
+			seq = "0"
+		}
+
+		for _, fileType := range style.FileTypes {
+			if !IsEzaKey(fileType) {
+				continue
+			}
+
+			parsed, err := ParseFileType(fileType)
+			if err != nil {
+				return "", fmt.Errorf("style %q in theme %q: %w", style.Name, t.Name, err)
+			}
+			out.WriteString(parsed.Value + "=" + seq + ":")
+		}
+	}
+
+	return out.String(), nil
+}
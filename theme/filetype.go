@@ -0,0 +1,186 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FilenamePrefix marks a filetype entry as an exact filename (ex: `file:Makefile`)
+const FilenamePrefix = "file:"
+
+// FileTypeKind is what a filetype entry matches against
+type FileTypeKind int
+
+const (
+	KindExtension FileTypeKind = iota // `.go`, matches names ending in the extension
+	KindFilename                      // `file:Makefile`, matches a specific filename
+	KindGlob                          // `*.tar.gz` or `*~`, matches names ending in the glob
+	KindKeyword                       // `DIR`, matches a system file type
+	KindEza                           // `eza:ur`, one of eza's UI elements
+)
+
+func (k FileTypeKind) String() string {
+	switch k {
+	case KindExtension:
+		return "extension"
+	case KindFilename:
+		return "filename"
+	case KindGlob:
+		return "glob"
+	case KindKeyword:
+		return "keyword"
+	case KindEza:
+		return "eza"
+	}
+	return "unknown"
+}
+
+// FileType is a single parsed filetype entry of a style
+type FileType struct {
+	Kind FileTypeKind
+	// Value is the entry without any prefix. Extensions keep their leading dot,
+	// globs keep their leading star, and keywords are uppercased.
+	Value string
+}
+
+// invalidFileTypeChars can't appear in an entry without breaking either
+// the dircolors file format or the LS_COLORS format
+const invalidFileTypeChars = " \t\n:="
+
+// ParseFileType will determine the kind of a filetype entry and validate it
+func ParseFileType(raw string) (FileType, error) {
+	entry := strings.TrimSpace(raw)
+
+	switch {
+	case entry == "":
+		return FileType{}, errors.New("empty filetype")
+	case strings.HasPrefix(entry, EzaPrefix):
+		key := strings.TrimPrefix(entry, EzaPrefix)
+		if _, ok := EzaKeys[key]; !ok {
+			return FileType{}, fmt.Errorf("unrecognized eza key %q", key)
+		}
+		return FileType{Kind: KindEza, Value: key}, nil
+	case strings.HasPrefix(entry, FilenamePrefix):
+		name := strings.TrimPrefix(entry, FilenamePrefix)
+		if err := checkPattern(entry, name); err != nil {
+			return FileType{}, err
+		}
+		if strings.ContainsAny(name, "/*?[") {
+			return FileType{}, fmt.Errorf("filename %q can't contain '/' or glob characters", name)
+		}
+		return FileType{Kind: KindFilename, Value: name}, nil
+	case strings.HasPrefix(entry, "."):
+		if err := checkPattern(entry, entry[1:]); err != nil {
+			return FileType{}, err
+		}
+		return FileType{Kind: KindExtension, Value: entry}, nil
+	case strings.HasPrefix(entry, "*"):
+		if err := checkPattern(entry, entry[1:]); err != nil {
+			return FileType{}, err
+		}
+		return FileType{Kind: KindGlob, Value: entry}, nil
+	}
+
+	keyword := strings.ToUpper(entry)
+	if _, ok := Keywords[keyword]; !ok {
+		return FileType{}, fmt.Errorf("unrecognized keyword %q (use %v%v for a filename)", entry, FilenamePrefix, entry)
+	}
+	return FileType{Kind: KindKeyword, Value: keyword}, nil
+}
+
+// checkPattern ensures the part of an entry after its prefix is usable
+func checkPattern(entry, pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("filetype %q is missing a pattern", entry)
+	}
+	if strings.ContainsAny(pattern, invalidFileTypeChars) {
+		return fmt.Errorf("filetype %q can't contain whitespace, ':' or '='", entry)
+	}
+	return nil
+}
+
+// LSColorsKey is the key written for this entry inside of LS_COLORS. Filenames become
+// a `*name` glob, as that's the closest match GNU ls offers. eza entries have no key.
+func (f FileType) LSColorsKey() string {
+	switch f.Kind {
+	case KindExtension, KindFilename:
+		return "*" + strings.TrimPrefix(f.Value, "*")
+	case KindGlob:
+		return f.Value
+	case KindKeyword:
+		return Keywords[f.Value]
+	}
+	return ""
+}
+
+// DirColorsKey is the keyword written for this entry inside of a dircolors file.
+// eza entries have no keyword.
+func (f FileType) DirColorsKey() string {
+	switch f.Kind {
+	case KindExtension, KindGlob, KindKeyword:
+		return f.Value
+	case KindFilename:
+		return "*" + f.Value
+	}
+	return ""
+}
+
+// ExampleName returns a filename that this entry would match, using base for any
+// part of the name the entry leaves open. Keywords and eza entries have no example name.
+func (f FileType) ExampleName(base string) string {
+	switch f.Kind {
+	case KindExtension:
+		return base + f.Value
+	case KindFilename:
+		return f.Value
+	case KindGlob:
+		return base + globExample(strings.TrimPrefix(f.Value, "*"))
+	}
+	return ""
+}
+
+// globExample replaces glob syntax with literal characters that match it
+func globExample(glob string) string {
+	var out strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			continue
+		case '?':
+			out.WriteByte('x')
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				out.WriteByte('[')
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if class == "" || class[0] == '!' || class[0] == '^' {
+				out.WriteByte('_')
+			} else {
+				out.WriteByte(class[0])
+			}
+			i += end
+		default:
+			out.WriteByte(glob[i])
+		}
+	}
+	return out.String()
+}
+
+// ParseFileTypes will parse and validate every filetype of the style
+func (s Style) ParseFileTypes() ([]FileType, error) {
+	var fileTypes []FileType
+	for _, raw := range s.FileTypes {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		fileType, err := ParseFileType(raw)
+		if err != nil {
+			return nil, fmt.Errorf("theme %q: style %q: %w", s.Theme, s.Name, err)
+		}
+		fileTypes = append(fileTypes, fileType)
+	}
+	return fileTypes, nil
+}
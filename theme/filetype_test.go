@@ -0,0 +1,92 @@
+package theme
+
+import "testing"
+
+func TestParseFileType(t *testing.T) {
+	tests := []struct {
+		raw       string
+		want      FileType
+		lsColors  string
+		dirColors string
+	}{
+		{".go", FileType{KindExtension, ".go"}, "*.go", ".go"},
+		{"  .tar.gz  ", FileType{KindExtension, ".tar.gz"}, "*.tar.gz", ".tar.gz"},
+		{"file:Makefile", FileType{KindFilename, "Makefile"}, "*Makefile", "*Makefile"},
+		{"*.tar.gz", FileType{KindGlob, "*.tar.gz"}, "*.tar.gz", "*.tar.gz"},
+		{"*~", FileType{KindGlob, "*~"}, "*~", "*~"},
+		{"*README*", FileType{KindGlob, "*README*"}, "*README*", "*README*"},
+		{"DIR", FileType{KindKeyword, "DIR"}, "di", "DIR"},
+		{"symlink", FileType{KindKeyword, "SYMLINK"}, "ln", "SYMLINK"},
+		{"OWT", FileType{KindKeyword, "OWT"}, "tw", "OWT"},
+		{"eza:ur", FileType{KindEza, "ur"}, "", ""},
+	}
+
+	for _, test := range tests {
+		got, err := ParseFileType(test.raw)
+		if err != nil {
+			t.Errorf("ParseFileType(%q) error: %v", test.raw, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseFileType(%q) = %+v, want %+v", test.raw, got, test.want)
+		}
+		if key := got.LSColorsKey(); key != test.lsColors {
+			t.Errorf("ParseFileType(%q).LSColorsKey() = %q, want %q", test.raw, key, test.lsColors)
+		}
+		if key := got.DirColorsKey(); key != test.dirColors {
+			t.Errorf("ParseFileType(%q).DirColorsKey() = %q, want %q", test.raw, key, test.dirColors)
+		}
+	}
+}
+
+func TestParseFileTypeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		".",
+		"*",
+		"file:",
+		"file:src/Makefile",
+		"file:*.go",
+		".tar gz",
+		"*.a:b",
+		".a=b",
+		"DIRR",
+		"Makefile",
+		"eza:zz",
+	}
+
+	for _, raw := range tests {
+		if got, err := ParseFileType(raw); err == nil {
+			t.Errorf("ParseFileType(%q) = %+v, want an error", raw, got)
+		}
+	}
+}
+
+func TestExampleName(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{".go", "base.go"},
+		{"file:Makefile", "Makefile"},
+		{"*.tar.gz", "base.tar.gz"},
+		{"*~", "base~"},
+		{"*README*", "baseREADME"},
+		{"*.[ch]", "base.c"},
+		{"*.[!o]", "base._"},
+		{"*.?z", "base.xz"},
+		{"DIR", ""},
+		{"eza:ur", ""},
+	}
+
+	for _, test := range tests {
+		fileType, err := ParseFileType(test.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := fileType.ExampleName("base"); got != test.want {
+			t.Errorf("ExampleName(%q) = %q, want %q", test.raw, got, test.want)
+		}
+	}
+}
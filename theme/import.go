@@ -0,0 +1,421 @@
+package theme
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// Entry is a single filetype along with the raw SGR sequence applied to it
+type Entry struct {
+	FileType string
+	Sequence string
+}
+
+// ignoredKeywords are dircolors keywords that don't describe a filetype
+var ignoredKeywords = map[string]bool{
+	"TERM":      true,
+	"COLORTERM": true,
+	"COLOR":     true,
+	"OPTIONS":   true,
+	"EIGHTBIT":  true,
+}
+
+// canonicalKeywords maps each LS_COLORS code back to the keyword stylish writes for it
+var canonicalKeywords = map[string]string{
+	"no": "NORMAL",
+	"fi": "FILE",
+	"rs": "RESET",
+	"di": "DIR",
+	"ln": "LINK",
+	"or": "ORPHAN",
+	"mi": "MISSING",
+	"pi": "FIFO",
+	"so": "SOCK",
+	"bd": "BLK",
+	"cd": "CHR",
+	"do": "DOOR",
+	"ex": "EXEC",
+	"lc": "LEFTCODE",
+	"rc": "RIGHTCODE",
+	"ec": "ENDCODE",
+	"su": "SETUID",
+	"sg": "SETGID",
+	"st": "STICKY",
+	"ow": "OTHER_WRITABLE",
+	"tw": "STICKY_OTHER_WRITABLE",
+	"ca": "CAPABILITY",
+	"mh": "MULTIHARDLINK",
+	"cl": "CLRTOEOL",
+}
+
+// keywordStyleNames are friendlier style names for groups led by a system keyword
+var keywordStyleNames = map[string]string{
+	"NORMAL":                "Normal Files",
+	"FILE":                  "Normal Files",
+	"DIR":                   "Directories",
+	"LINK":                  "Links",
+	"ORPHAN":                "Orphans",
+	"MISSING":               "Missing",
+	"FIFO":                  "Pipes",
+	"SOCK":                  "Sockets",
+	"BLK":                   "Block Devices",
+	"CHR":                   "Character Devices",
+	"DOOR":                  "Doors",
+	"EXEC":                  "Executables",
+	"SETUID":                "Setuid",
+	"SETGID":                "Setgid",
+	"STICKY":                "Sticky",
+	"OTHER_WRITABLE":        "Other Writable",
+	"STICKY_OTHER_WRITABLE": "Sticky Other Writable",
+	"RESET":                 "Reset",
+	"CAPABILITY":            "Capabilities",
+	"MULTIHARDLINK":         "Hard Links",
+}
+
+// ParseImport will parse either the contents of a dircolors file or an LS_COLORS value,
+// including the `LS_COLORS='...'; export LS_COLORS` and `setenv LS_COLORS '...'` forms that
+// `dircolors` itself outputs for Bourne shells and csh
+func ParseImport(data string) ([]Entry, error) {
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "LS_COLORS='") {
+		trimmed = strings.TrimPrefix(trimmed, "LS_COLORS='")
+		trimmed = strings.TrimSuffix(trimmed, "export LS_COLORS")
+		trimmed = strings.TrimSpace(trimmed)
+		trimmed = strings.TrimSuffix(trimmed, ";")
+		trimmed = strings.TrimSuffix(trimmed, "'")
+		return ParseLSColors(strings.ReplaceAll(trimmed, `'\''`, `'`))
+	}
+	if strings.HasPrefix(trimmed, "setenv LS_COLORS '") {
+		trimmed = strings.TrimPrefix(trimmed, "setenv LS_COLORS '")
+		trimmed = strings.TrimSuffix(trimmed, ";")
+		trimmed = strings.TrimSuffix(trimmed, "'")
+		return ParseLSColors(strings.NewReplacer(`'\''`, `'`, `\!`, `!`).Replace(trimmed))
+	}
+
+	if !strings.ContainsAny(trimmed, " \t\n") && strings.Contains(trimmed, "=") {
+		return ParseLSColors(trimmed)
+	}
+
+	return ParseDirColors(strings.NewReader(data))
+}
+
+// ParseDirColors will read the filetype entries out of a dircolors database,
+// skipping comments and terminal selection keywords
+func ParseDirColors(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := stripComment(scanner.Text())
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %v: missing color sequence for %q", lineNum, fields[0])
+		}
+
+		key, seq := fields[0], fields[1]
+		if ignoredKeywords[strings.ToUpper(key)] {
+			continue
+		}
+
+		fileType, err := importFileType(key, true)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %w", lineNum, err)
+		}
+		entries = append(entries, Entry{FileType: fileType, Sequence: seq})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// stripComment removes a trailing comment. Like dircolors, a `#` only starts a comment
+// at the start of a line or after whitespace, so entries such as `*#` are kept intact.
+func stripComment(line string) string {
+	for i, r := range line {
+		if r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// ParseLSColors will split an LS_COLORS value into its entries
+func ParseLSColors(value string) ([]Entry, error) {
+	var entries []Entry
+
+	for _, item := range strings.Split(value, ":") {
+		if item == "" {
+			continue
+		}
+
+		key, seq, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed LS_COLORS entry %q", item)
+		}
+
+		// `ln=target` colors links like the file they point to, which stylish can't represent
+		if seq == "target" {
+			continue
+		}
+
+		fileType, err := importFileType(key, false)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{FileType: fileType, Sequence: seq})
+	}
+
+	return entries, nil
+}
+
+// importFileType converts a dircolors keyword or LS_COLORS key into the filetype stylish stores
+func importFileType(key string, isDircolors bool) (string, error) {
+	if strings.HasPrefix(key, "*.") && !strings.ContainsAny(key[2:], "*?[") {
+		return key[1:], nil
+	}
+	if strings.HasPrefix(key, ".") || strings.HasPrefix(key, "*") {
+		return key, nil
+	}
+
+	code := key
+	if isDircolors {
+		var ok bool
+		code, ok = Keywords[strings.ToUpper(key)]
+		if !ok {
+			return "", fmt.Errorf("unrecognized keyword %q", key)
+		}
+	}
+
+	keyword, ok := canonicalKeywords[code]
+	if !ok {
+		return "", fmt.Errorf("unrecognized LS_COLORS key %q", key)
+	}
+
+	return keyword, nil
+}
+
+// ParseSequence will convert an SGR sequence (ex: `01;38;5;203`) into a style's attributes and colors.
+// Basic, bright, 8-bit and truecolor codes all become hex codes. Unknown codes are ignored.
+func ParseSequence(seq string) Style {
+	var style Style
+
+	var codes []int
+	for _, part := range strings.Split(seq, ";") {
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			continue
+		}
+		codes = append(codes, code)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+		switch {
+		case code == 0:
+			style = Style{}
+		case code == 1:
+			style.Bold = true
+		case code == 2:
+			style.Dim = true
+		case code == 3:
+			style.Italic = true
+		case code == 4:
+			style.Under = true
+		case code == 5 || code == 6:
+			style.Blink = true
+		case code == 7:
+			style.Reverse = true
+		case code == 8:
+			style.Hidden = true
+		case code == 9:
+			style.Strike = true
+		case code == 53:
+			style.Overline = true
+		case code >= 30 && code <= 37:
+			style.Fore = ansiToHex(code - 30)
+		case code >= 90 && code <= 97:
+			style.Fore = ansiToHex(code - 90 + 8)
+		case code == 39:
+			style.Fore = ""
+		case code >= 40 && code <= 47:
+			style.Back = ansiToHex(code - 40)
+		case code >= 100 && code <= 107:
+			style.Back = ansiToHex(code - 100 + 8)
+		case code == 49:
+			style.Back = ""
+		case code == 38 || code == 48:
+			hex, used := extendedToHex(codes[i+1:])
+			i += used
+			if code == 38 {
+				style.Fore = hex
+			} else {
+				style.Back = hex
+			}
+		}
+	}
+
+	return style
+}
+
+// extendedToHex converts the arguments following a 38 or 48 code into a hex code,
+// returning how many of the arguments it consumed
+func extendedToHex(args []int) (string, int) {
+	if len(args) >= 2 && args[0] == 5 {
+		return ansiToHex(args[1]), 2
+	}
+	if len(args) >= 4 && args[0] == 2 {
+		return fmt.Sprintf("%02X%02X%02X", args[1]&0xFF, args[2]&0xFF, args[3]&0xFF), 4
+	}
+	return "", len(args)
+}
+
+// ansiToHex converts an xterm-256 palette index into a hex code without the leading #
+func ansiToHex(index int) string {
+	if index < 0 || index > 255 {
+		return ""
+	}
+	return strings.ToUpper(strings.TrimPrefix(termenv.ANSI256Color(index).String(), "#"))
+}
+
+// FromEntries will build a theme out of imported entries. Entries sharing the same
+// attributes and colors are grouped into a single style. If a filetype shows up more
+// than once, its last entry wins, just like it would in `ls`.
+func FromEntries(name string, entries []Entry) Theme {
+	outTheme := Theme{
+		Name: name,
+		Path: filepath.Join(ThemeConfigFolder, name),
+	}
+
+	last := make(map[string]int)
+	for i, entry := range entries {
+		last[entry.FileType] = i
+	}
+
+	groups := make(map[string]int)
+	for i, entry := range entries {
+		if last[entry.FileType] != i {
+			continue
+		}
+
+		parsed := ParseSequence(entry.Sequence)
+		key := parsed.Sequence()
+
+		idx, ok := groups[key]
+		if !ok {
+			style := NewStyle(name, "")
+			style.Bold, style.Dim, style.Italic = parsed.Bold, parsed.Dim, parsed.Italic
+			style.Under, style.Blink, style.Reverse = parsed.Under, parsed.Blink, parsed.Reverse
+			style.Hidden, style.Strike, style.Overline = parsed.Hidden, parsed.Strike, parsed.Overline
+			style.Fore, style.Back = parsed.Fore, parsed.Back
+
+			idx = len(outTheme.Styles)
+			groups[key] = idx
+			outTheme.Styles = append(outTheme.Styles, style)
+		}
+
+		outTheme.Styles[idx].FileTypes = append(outTheme.Styles[idx].FileTypes, entry.FileType)
+	}
+
+	used := make(map[string]bool)
+	for i := range outTheme.Styles {
+		outTheme.Styles[i].Name = importedStyleName(outTheme.Styles[i].FileTypes, used)
+	}
+
+	return outTheme
+}
+
+// importedStyleName picks a unique name for an imported style based on its filetypes
+func importedStyleName(fileTypes []string, used map[string]bool) string {
+	base, ok := keywordStyleNames[fileTypes[0]]
+	if !ok {
+		base = strings.TrimLeft(fileTypes[0], ".*")
+		if base == "" {
+			base = "Imported"
+		}
+		if len(fileTypes) > 1 {
+			base = fmt.Sprintf("%v +%v", base, len(fileTypes)-1)
+		}
+	}
+	base = strings.ReplaceAll(base, string(filepath.Separator), "_")
+
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%v %v", base, i)
+	}
+	used[name] = true
+
+	return name
+}
+
+// SaveNew will write every style of an imported theme into a brand new theme folder
+func (t Theme) SaveNew() error {
+	if _, err := os.Stat(t.Path); err == nil {
+		return fmt.Errorf("theme %q already exists", t.Name)
+	}
+
+	if err := os.MkdirAll(t.Path, 0755); err != nil {
+		return fmt.Errorf("theme %q: creating folder: %w", t.Name, err)
+	}
+
+	for _, style := range t.Styles {
+		if err := style.SaveStyle(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadImportSource returns the contents of the file at path,
+// or the current $LS_COLORS value when path is empty
+func ReadImportSource(path string) (string, error) {
+	if path == "" {
+		value := os.Getenv("LS_COLORS")
+		if value == "" {
+			return "", errors.New("$LS_COLORS is empty, give a file to import instead")
+		}
+		return value, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading import file: %w", err)
+	}
+	return string(data), nil
+}
+
+// Import will parse a dircolors file or LS_COLORS value and save it as a new theme
+func Import(name, data string) (Theme, error) {
+	if name == "" {
+		return Theme{}, errors.New("tried to import a theme with an empty name")
+	}
+
+	entries, err := ParseImport(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("importing theme %q: %w", name, err)
+	}
+
+	outTheme := FromEntries(name, entries)
+	if err := outTheme.SaveNew(); err != nil {
+		return outTheme, err
+	}
+
+	return outTheme, nil
+}
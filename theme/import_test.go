@@ -0,0 +1,128 @@
+package theme
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseImport(t *testing.T) {
+	want := []Entry{{"DIR", "01;34"}, {".go", "38;2;0;173;216"}, {"*README*", "4"}}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"raw value", "di=01;34:*.go=38;2;0;173;216:*README*=4:"},
+		{"bourne shell", "LS_COLORS='di=01;34:*.go=38;2;0;173;216:*README*=4:';\nexport LS_COLORS\n"},
+		{"csh", "setenv LS_COLORS 'di=01;34:*.go=38;2;0;173;216:*README*=4:'\n"},
+		{"dircolors", "# comment\nTERM xterm*\nDIR 01;34\n.go 38;2;0;173;216 # Go\n*README* 4\n"},
+	}
+
+	for _, test := range tests {
+		got, err := ParseImport(test.data)
+		if err != nil {
+			t.Errorf("%v: ParseImport() error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: ParseImport() = %v, want %v", test.name, got, want)
+		}
+	}
+}
+
+func TestParseImportQuoting(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"bourne shell", `LS_COLORS='*it'\''s=01:'; export LS_COLORS`},
+		{"csh", `setenv LS_COLORS '*it'\''s\!=01:'`},
+	}
+
+	for _, test := range tests {
+		got, err := ParseImport(test.data)
+		if err != nil {
+			t.Errorf("%v: ParseImport() error: %v", test.name, err)
+			continue
+		}
+		if len(got) != 1 || !strings.HasPrefix(got[0].FileType, "*it's") {
+			t.Errorf("%v: ParseImport() = %v, want the quote unescaped", test.name, got)
+		}
+	}
+}
+
+func TestParseImportErrors(t *testing.T) {
+	tests := []string{
+		"di=01;34:nope",
+		"zz=01",
+		"DIRR 01;34\n",
+		"DIR\n",
+	}
+
+	for _, data := range tests {
+		if _, err := ParseImport(data); err == nil {
+			t.Errorf("ParseImport(%q) didn't return an error", data)
+		}
+	}
+}
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		seq  string
+		want Style
+	}{
+		{"0", Style{}},
+		{"01;34", Style{Bold: true, Fore: "000080"}},
+		{"1;2;3;4;5;7;8;9;53", Style{Bold: true, Dim: true, Italic: true, Under: true, Blink: true, Reverse: true, Hidden: true, Strike: true, Overline: true}},
+		{"91;107", Style{Fore: "FF0000", Back: "FFFFFF"}},
+		{"38;5;203", Style{Fore: "FF5F5F"}},
+		{"38;2;0;173;216;48;2;1;2;3", Style{Fore: "00ADD8", Back: "010203"}},
+		{"31;39", Style{}},
+		{"1;0;4", Style{Under: true}},
+	}
+
+	for _, test := range tests {
+		if got := ParseSequence(test.seq); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseSequence(%q) = %+v, want %+v", test.seq, got, test.want)
+		}
+	}
+}
+
+func TestLSColorsRoundTrip(t *testing.T) {
+	original := Theme{Name: "test", Styles: []Style{
+		{Name: "Dirs", Bold: true, Fore: "5F87FF", FileTypes: []string{"DIR", "STICKY"}},
+		{Name: "Go", Fore: "00ADD8", Back: "101010", FileTypes: []string{".go", "file:go.mod"}},
+		{Name: "Archives", Under: true, Italic: true, Fore: "FF5F5F", FileTypes: []string{"*.tar.gz", ".zip"}},
+		{Name: "Plain", FileTypes: []string{"FILE"}},
+	}}
+
+	value, err := original.LSColors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ParseImport(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := FromEntries("test", entries)
+
+	roundTripped, err := imported.LSColors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	split := func(value string) []string {
+		entries := strings.Split(strings.TrimSuffix(value, ":"), ":")
+		slices.Sort(entries)
+		return entries
+	}
+	if got, want := split(roundTripped), split(value); !slices.Equal(got, want) {
+		t.Errorf("round trip = %q, want %q", got, want)
+	}
+	if len(imported.Styles) != len(original.Styles) {
+		t.Errorf("imported %v styles, want %v", len(imported.Styles), len(original.Styles))
+	}
+}
@@ -0,0 +1,176 @@
+package theme
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Problem is a single issue found while linting a theme
+type Problem struct {
+	Style   string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Style == "" {
+		return p.Message
+	}
+	return fmt.Sprintf("%v: %v", p.Style, p.Message)
+}
+
+// Lint will check a theme for anything that would be silently dropped or overridden when applied:
+// invalid filetypes, filetypes claimed by more than one style, malformed hex codes, styles
+// without any filetypes, style files whose `theme:` or `name:` disagree with their path, and
+// styles that can't be told apart once downsampled to 256 colors.
+func (t Theme) Lint() []Problem {
+	var problems []Problem
+
+	// Every style claiming each LS_COLORS key, in output order, along with the entry it was written as
+	type claim struct{ style, raw string }
+	claims := make(map[string][]claim)
+	var keys []string
+
+	for _, style := range t.Styles {
+		if style.fileName != "" && style.Name != style.fileName {
+			problems = append(problems, Problem{style.Name, fmt.Sprintf("name %q doesn't match its file %q", style.Name, style.fileName+".yaml")})
+		}
+		if style.Theme != t.Name {
+			problems = append(problems, Problem{style.Name, fmt.Sprintf("theme %q doesn't match the theme folder %q", style.Theme, t.Name)})
+		}
+
+		colors := [][2]string{{"fore", style.Fore}, {"back", style.Back}}
+		for _, background := range []string{BackgroundLight, BackgroundDark} {
+			if variant := style.Variant(background); variant != nil {
+				colors = append(colors, [2]string{background + " fore", variant.Fore}, [2]string{background + " back", variant.Back})
+			}
+		}
+		for _, color := range colors {
+			if IsPaletteRef(color[1]) {
+				if _, err := style.ResolveColor(color[1]); err != nil {
+					problems = append(problems, Problem{style.Name, fmt.Sprintf("%v color: %v", color[0], err)})
+				}
+			} else if color[1] != "" && ValidHexCode(strings.TrimPrefix(color[1], "#")) != nil {
+				problems = append(problems, Problem{style.Name, fmt.Sprintf("%v color %q isn't a valid hex code", color[0], color[1])})
+			}
+		}
+
+		valid := 0
+		for _, raw := range style.FileTypes {
+			if strings.TrimSpace(raw) == "" {
+				continue
+			}
+
+			fileType, err := ParseFileType(raw)
+			if err != nil {
+				problems = append(problems, Problem{style.Name, err.Error()})
+				continue
+			}
+			valid++
+
+			key := fileType.LSColorsKey()
+			if fileType.Kind == KindEza {
+				key = EzaPrefix + fileType.Value
+			}
+			if len(claims[key]) == 0 {
+				keys = append(keys, key)
+			}
+			claims[key] = append(claims[key], claim{style.Name, strings.TrimSpace(raw)})
+		}
+
+		if valid == 0 {
+			problems = append(problems, Problem{style.Name, "has no filetypes, so it won't be applied"})
+		}
+	}
+
+	for _, key := range keys {
+		if len(claims[key]) < 2 {
+			continue
+		}
+
+		var names []string
+		var entry string
+		for _, claim := range claims[key] {
+			names = append(names, fmt.Sprintf("%q", claim.style))
+			entry = claim.raw
+		}
+		winner := claims[key][len(claims[key])-1].style
+		problems = append(problems, Problem{"", fmt.Sprintf("%v is claimed by %v; %q wins", entry, strings.Join(names, ", "), winner)})
+	}
+
+	for _, name := range t.Palette.Names() {
+		if err := ValidPaletteName(name); err != nil {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: %v", PaletteFile, err)})
+		}
+		if hex := t.Palette[name]; ValidHexCode(hex) != nil {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: %q isn't a valid hex code for %q", PaletteFile, hex, name)})
+		}
+	}
+
+	problems = append(problems, t.lintManifest()...)
+	problems = append(problems, t.lintRemovals()...)
+	problems = append(problems, t.lintDownsampled()...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Style != "" && problems[j].Style == ""
+	})
+
+	return problems
+}
+
+// lintManifest checks the manifest's metadata and style order
+func (t Theme) lintManifest() []Problem {
+	var problems []Problem
+	manifest := t.Manifest
+
+	if manifest.Background != "" && manifest.Background != BackgroundDark && manifest.Background != BackgroundLight {
+		problems = append(problems, Problem{"", fmt.Sprintf("%v: background %q should be %q or %q",
+			ManifestFile, manifest.Background, BackgroundDark, BackgroundLight)})
+	}
+
+	for _, color := range [][2]string{{"fore", manifest.Fore}, {"back", manifest.Back}} {
+		if color[1] != "" && ValidHexCode(color[1]) != nil {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: %v color %q isn't a valid hex code", ManifestFile, color[0], color[1])})
+		}
+	}
+
+	for _, name := range manifest.Order {
+		if !t.DoesStyleExist(name) {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: order lists %q, which isn't a style", ManifestFile, name)})
+		}
+	}
+
+	return problems
+}
+
+// lintRemovals checks that everything the manifest removes could have been inherited in the first place
+func (t Theme) lintRemovals() []Problem {
+	var problems []Problem
+	removals := t.Manifest.Remove
+
+	if t.parent == nil {
+		if len(removals.Styles) > 0 || len(removals.FileTypes) > 0 {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: removals don't do anything without `extends`", ManifestFile)})
+		}
+		return problems
+	}
+
+	for _, name := range removals.Styles {
+		if !t.parent.DoesStyleExist(name) {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: removes style %q, which %q doesn't have", ManifestFile, name, t.parent.Name)})
+		}
+	}
+
+	for _, fileType := range removals.FileTypes {
+		found := false
+		for _, style := range t.parent.Styles {
+			found = found || slices.Contains(style.FileTypes, fileType)
+		}
+		if !found {
+			problems = append(problems, Problem{"", fmt.Sprintf("%v: removes filetype %v, which %q doesn't use", ManifestFile, fileType, t.parent.Name)})
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,29 @@
+package theme
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLintClaims(t *testing.T) {
+	th := Theme{Name: "test", Styles: []Style{
+		{Theme: "test", Name: "Source", FileTypes: []string{".go", "DIR"}},
+		{Theme: "test", Name: "Go", FileTypes: []string{"*.go"}},
+		{Theme: "test", Name: "Dirs", FileTypes: []string{"DIRR"}},
+	}}
+
+	var messages []string
+	for _, problem := range th.Lint() {
+		messages = append(messages, problem.String())
+	}
+
+	want := []string{
+		`Dirs: unrecognized keyword "DIRR" (use file:DIRR for a filename)`,
+		`*.go is claimed by "Source", "Go"; "Go" wins`,
+	}
+	for _, message := range want {
+		if !slices.Contains(messages, message) {
+			t.Errorf("Lint() = %q, missing %q", messages, message)
+		}
+	}
+}
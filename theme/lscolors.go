@@ -0,0 +1,97 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keywords maps each system keyword understood by dircolors to the two-letter
+// code it's written as inside of LS_COLORS. Aliases match the ones dircolors accepts.
+var Keywords = map[string]string{
+	"NORMAL":                "no",
+	"NORM":                  "no",
+	"FILE":                  "fi",
+	"RESET":                 "rs",
+	"DIR":                   "di",
+	"LNK":                   "ln",
+	"LINK":                  "ln",
+	"SYMLINK":               "ln",
+	"ORPHAN":                "or",
+	"MISSING":               "mi",
+	"FIFO":                  "pi",
+	"PIPE":                  "pi",
+	"SOCK":                  "so",
+	"BLK":                   "bd",
+	"BLOCK":                 "bd",
+	"CHR":                   "cd",
+	"CHAR":                  "cd",
+	"DOOR":                  "do",
+	"EXEC":                  "ex",
+	"LEFT":                  "lc",
+	"LEFTCODE":              "lc",
+	"RIGHT":                 "rc",
+	"RIGHTCODE":             "rc",
+	"END":                   "ec",
+	"ENDCODE":               "ec",
+	"SUID":                  "su",
+	"SETUID":                "su",
+	"SGID":                  "sg",
+	"SETGID":                "sg",
+	"STICKY":                "st",
+	"OTHER_WRITABLE":        "ow",
+	"OWR":                   "ow",
+	"STICKY_OTHER_WRITABLE": "tw",
+	"OWT":                   "tw",
+	"CAPABILITY":            "ca",
+	"MULTIHARDLINK":         "mh",
+	"CLRTOEOL":              "cl",
+}
+
+// LSColors converts all of a theme's styles into the value expected by the LS_COLORS
+// environment variable, without needing the `dircolors` binary. Styles are emitted in order,
+// so later styles win when two of them claim the same filetype. Like `dircolors`, every
+// entry is terminated with a colon.
+func (t Theme) LSColors() (string, error) {
+	var out strings.Builder
+
+	for _, style := range t.Styles {
+		if len(style.FileTypes) < 1 {
+			continue
+		}
+
+		seq := style.Sequence()
+		if seq == "" {
+			seq = "0"
+		}
+
+		for _, fileType := range style.FileTypes {
+			key, err := Key(fileType)
+			if err != nil {
+				return "", fmt.Errorf("style %q in theme %q: %w", style.Name, t.Name, err)
+			}
+			if key == "" {
+				continue
+			}
+			out.WriteString(key + "=" + seq + ":")
+		}
+	}
+
+	return out.String(), nil
+}
+
+// Key converts a single filetype entry into its LS_COLORS key.
+// Extensions such as `.go` become `*.go`, filenames such as `file:Makefile` become
+// `*Makefile`, globs are kept as-is, and system keywords become their two-letter code.
+// Empty entries and eza UI entries have no key.
+func Key(fileType string) (string, error) {
+	if strings.TrimSpace(fileType) == "" {
+		return "", nil
+	}
+
+	parsed, err := ParseFileType(fileType)
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.LSColorsKey(), nil
+}
@@ -0,0 +1,54 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   Style
+		profile termenv.Profile
+		want    string
+	}{
+		{"empty", Style{}, termenv.TrueColor, ""},
+		{"bold", Style{Bold: true}, termenv.TrueColor, "1"},
+		{"attributes in SGR order", Style{Overline: true, Under: true, Bold: true, Dim: true}, termenv.TrueColor, "1;2;4;53"},
+		{"truecolor", Style{Fore: "EF476F"}, termenv.TrueColor, "38;2;239;71;111"},
+		{"fore and back", Style{Bold: true, Fore: "FFFFFF", Back: "000000"}, termenv.TrueColor, "1;38;2;255;255;255;48;2;0;0;0"},
+		{"256 colors", Style{Fore: "FF0000"}, termenv.ANSI256, "38;5;196"},
+		{"16 colors", Style{Fore: "FF0000"}, termenv.ANSI, "91"},
+		{"no colors", Style{Italic: true, Fore: "FF0000"}, termenv.Ascii, "3"},
+	}
+
+	for _, test := range tests {
+		test.style.profile = test.profile
+		if got := test.style.Sequence(); got != test.want {
+			t.Errorf("%v: Sequence() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLSColors(t *testing.T) {
+	th := Theme{Name: "test", Styles: []Style{
+		{Name: "Dirs", Bold: true, Fore: "0000FF", FileTypes: []string{"DIR", "symlink"}},
+		{Name: "Empty"},
+		{Name: "Plain", FileTypes: []string{".txt", "file:Makefile", "*.tar.gz", "eza:ur", ""}},
+	}}
+
+	got, err := th.LSColors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "di=1;38;2;0;0;255:ln=1;38;2;0;0;255:*.txt=0:*Makefile=0:*.tar.gz=0:"
+	if got != want {
+		t.Errorf("LSColors() = %q, want %q", got, want)
+	}
+
+	th.Styles = append(th.Styles, Style{Name: "Broken", FileTypes: []string{"DIRR"}})
+	if _, err := th.LSColors(); err == nil {
+		t.Error("LSColors() didn't return an error for an unknown keyword")
+	}
+}
@@ -0,0 +1,336 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the file inside of a theme's folder that describes the theme itself
+const ManifestFile = "theme.yaml"
+
+// ManifestSchema is the newest manifest schema this version of stylish understands.
+// Manifests with an older schema, or themes without a manifest at all, are migrated when loaded.
+const ManifestSchema = 1
+
+// The backgrounds a theme can be designed for
+const (
+	BackgroundDark  = "dark"
+	BackgroundLight = "light"
+)
+
+// Manifest holds the settings of a theme that aren't tied to any one style
+type Manifest struct {
+	Schema int `yaml:"schema"`
+
+	// Title is the theme's display name. The folder name is still used to refer to the theme.
+	Title       string `yaml:"title,omitempty"`
+	Author      string `yaml:"author,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	// Background is what the theme was designed to be used on, either dark or light.
+	// Fore and Back are the terminal colors it was designed around, as hex codes.
+	Background string `yaml:"background,omitempty"`
+	Fore       string `yaml:"fore,omitempty"`
+	Back       string `yaml:"back,omitempty"`
+
+	// Extends names a theme whose styles and palette are inherited
+	Extends string   `yaml:"extends,omitempty"`
+	Remove  Removals `yaml:"remove,omitempty"`
+
+	// Order lists style names in the order they're applied, which decides who wins when two
+	// styles claim the same filetype. Styles that aren't listed come after, alphabetically.
+	Order []string `yaml:"order,omitempty"`
+}
+
+// Removals are the parts of an extended theme that aren't inherited
+type Removals struct {
+	Styles    []string `yaml:"styles,omitempty"`
+	FileTypes []string `yaml:"filetypes,omitempty"`
+}
+
+// StyleSource describes where a style of a theme is defined
+type StyleSource int
+
+const (
+	SourceLocal      StyleSource = iota // Only defined by the theme itself
+	SourceInherited                     // Taken as-is from the extended theme
+	SourceOverridden                    // Defined by the theme in place of the extended theme's style
+)
+
+func (s StyleSource) String() string {
+	switch s {
+	case SourceInherited:
+		return "inherited"
+	case SourceOverridden:
+		return "overridden"
+	}
+	return "local"
+}
+
+// Source reports whether the style was inherited from the theme's parent, overrides one of
+// the parent's styles, or belongs to the theme alone
+func (s Style) Source() StyleSource {
+	switch {
+	case s.inherited && s.local:
+		return SourceOverridden
+	case s.inherited:
+		return SourceInherited
+	}
+	return SourceLocal
+}
+
+// loadManifest reads the manifest from the root of fsys. Themes without one get an empty manifest.
+func loadManifest(fsys fs.FS) (Manifest, error) {
+	var manifest Manifest
+
+	file, err := fsys.Open(ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	} else if err != nil {
+		return manifest, err
+	}
+	defer file.Close()
+
+	if err := yaml.NewDecoder(file).Decode(&manifest); err != nil && err != io.EOF {
+		return manifest, fmt.Errorf("%v: %w", ManifestFile, err)
+	}
+	if manifest.Schema > ManifestSchema {
+		return manifest, fmt.Errorf("%v uses schema %v, but this version of stylish only understands up to %v",
+			ManifestFile, manifest.Schema, ManifestSchema)
+	}
+	return manifest, nil
+}
+
+// SaveManifest writes the theme's manifest into its folder, stamped with the current schema
+func (t Theme) SaveManifest() error {
+	path := filepath.Join(ThemeConfigFolder, t.Name, ManifestFile)
+	t.Manifest.Schema = ManifestSchema
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("theme %q: %v: %w", t.Name, ManifestFile, err)
+	}
+	defer file.Close()
+
+	if err := yaml.NewEncoder(file).Encode(t.Manifest); err != nil {
+		return fmt.Errorf("theme %q: %v: %w", t.Name, ManifestFile, err)
+	}
+	return nil
+}
+
+// Extend creates a new, empty theme that inherits everything from parent
+func Extend(name, parent string) (Theme, error) {
+	if name == "" {
+		return Theme{}, errors.New("tried to create a theme with an empty name")
+	}
+	if _, err := os.Stat(filepath.Join(ThemeConfigFolder, parent)); err != nil {
+		return Theme{}, fmt.Errorf("theme %q: extending %q: %w", name, parent, err)
+	}
+
+	path := filepath.Join(ThemeConfigFolder, name)
+	if _, err := os.Stat(path); err == nil {
+		return Theme{}, fmt.Errorf("theme %q already exists", name)
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return Theme{}, fmt.Errorf("theme %q: creating folder: %w", name, err)
+	}
+
+	t := Theme{Name: name, Manifest: Manifest{Schema: ManifestSchema, Extends: parent}}
+	if err := t.SaveManifest(); err != nil {
+		return Theme{}, err
+	}
+
+	return GetTheme(name)
+}
+
+// loadParent loads the theme t extends from the config folder. chain holds every theme
+// already being loaded below it, so a theme that ends up extending itself is caught.
+func (t Theme) loadParent(chain []string) (*Theme, error) {
+	if slices.Contains(chain, t.Manifest.Extends) {
+		cycle := append(slices.Clone(chain), t.Manifest.Extends)
+		return nil, fmt.Errorf("theme %q: extends itself (%v)", t.Name, strings.Join(cycle, " -> "))
+	}
+
+	path := filepath.Join(ThemeConfigFolder, t.Manifest.Extends)
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("theme %q: extending %q: %w", t.Name, t.Manifest.Extends, err)
+	}
+
+	parent, err := load(os.DirFS(path), t.Manifest.Extends, chain)
+	if err != nil {
+		return nil, err
+	}
+	parent.Path = path
+	return &parent, nil
+}
+
+// inherit merges the parent's palette and styles underneath the theme's own.
+// Styles the theme defines itself replace the parent's style of the same name, and anything
+// listed under the manifest's removals is left out.
+func (t *Theme) inherit(parent *Theme) {
+	t.parent = parent
+
+	palette := make(Palette, len(parent.Palette)+len(t.Palette))
+	for name, hex := range parent.Palette {
+		palette[name] = hex
+	}
+	for name, hex := range t.Palette {
+		palette[name] = hex
+	}
+	t.Palette = palette
+
+	own := make(map[string]int, len(t.Styles))
+	for i, style := range t.Styles {
+		own[style.Name] = i
+	}
+
+	styles := make([]Style, 0, len(parent.Styles)+len(t.Styles))
+	for _, style := range parent.Styles {
+		if slices.Contains(t.Manifest.Remove.Styles, style.Name) {
+			continue
+		}
+		if i, ok := own[style.Name]; ok {
+			t.Styles[i].inherited = true
+			continue
+		}
+		styles = append(styles, t.adopt(style))
+	}
+	styles = append(styles, t.Styles...)
+
+	for i := range styles {
+		styles[i].palette = palette
+	}
+	t.Styles = styles
+}
+
+// sortStyles puts the theme's styles in the manifest's order, followed by any it doesn't list
+func (t *Theme) sortStyles() {
+	rank := func(style Style) int {
+		if i := slices.Index(t.Manifest.Order, style.Name); i >= 0 {
+			return i
+		}
+		return len(t.Manifest.Order)
+	}
+
+	sort.SliceStable(t.Styles, func(i, j int) bool {
+		ri, rj := rank(t.Styles[i]), rank(t.Styles[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return t.Styles[i].Name < t.Styles[j].Name
+	})
+}
+
+// SetOrder saves names as the order the theme's styles are applied in
+func (t *Theme) SetOrder(names []string) error {
+	t.Manifest.Order = slices.Clone(names)
+	t.sortStyles()
+	return t.SaveManifest()
+}
+
+// migrateManifest brings the manifest of a theme loaded from fsys up to the current schema.
+// Themes from before manifests existed kept their metadata in a README, so whatever
+// can be recognized there is carried over. Only the theme in memory is migrated, and
+// theme.yaml is written the next time the manifest is saved.
+func (t *Theme) migrateManifest(fsys fs.FS) {
+	if t.Manifest.Schema >= ManifestSchema {
+		return
+	}
+
+	if t.Manifest.Schema < 1 {
+		if readme, err := fs.ReadFile(fsys, "README.md"); err == nil {
+			t.Manifest.fromReadme(string(readme))
+		}
+	}
+
+	t.Manifest.Schema = ManifestSchema
+}
+
+// fromReadme fills in any empty metadata from a theme README laid out like the default theme's:
+// a `# Title` heading, followed by `Default Foreground: #hex` and `Default Background: #hex` lines
+func (m *Manifest) fromReadme(readme string) {
+	for _, line := range strings.Split(readme, "\n") {
+		line = strings.TrimSpace(line)
+
+		if title, ok := strings.CutPrefix(line, "# "); ok && m.Title == "" {
+			m.Title = strings.TrimSpace(title)
+		} else if fore, ok := strings.CutPrefix(line, "Default Foreground:"); ok && m.Fore == "" {
+			m.Fore = strings.TrimPrefix(strings.TrimSpace(fore), "#")
+		} else if back, ok := strings.CutPrefix(line, "Default Background:"); ok && m.Back == "" {
+			m.Back = strings.TrimPrefix(strings.TrimSpace(back), "#")
+		}
+	}
+
+	if m.Background == "" && ValidHexCode(m.Back) == nil {
+		if color, err := colorful.Hex("#" + m.Back); err == nil {
+			if _, _, l := color.Hsl(); l < 0.5 {
+				m.Background = BackgroundDark
+			} else {
+				m.Background = BackgroundLight
+			}
+		}
+	}
+}
+
+// inheritedStyle is the parent's version of the named style, as the theme would inherit it
+func (t Theme) inheritedStyle(name string) (Style, bool) {
+	if t.parent == nil {
+		return Style{}, false
+	}
+	for _, style := range t.parent.Styles {
+		if style.Name == name {
+			return t.adopt(style), true
+		}
+	}
+	return Style{}, false
+}
+
+// adopt turns one of the parent's styles into one of the theme's, minus any removed filetypes
+func (t Theme) adopt(style Style) Style {
+	style.Theme = t.Name
+	style.inherited = true
+	style.local = false
+	style.fileName = ""
+	style.palette = t.Palette
+	style.FileTypes = slices.DeleteFunc(slices.Clone(style.FileTypes), func(fileType string) bool {
+		return slices.Contains(t.Manifest.Remove.FileTypes, fileType)
+	})
+	return style
+}
+
+// RevertStyle throws away the theme's override of an inherited style, returning the parent's version
+func (t *Theme) RevertStyle(name string) (Style, error) {
+	inherited, ok := t.inheritedStyle(name)
+	if !ok {
+		return Style{}, fmt.Errorf("theme %q: style %q isn't inherited", t.Name, name)
+	}
+
+	for i := range t.Styles {
+		if t.Styles[i].Name == name {
+			if err := t.removeStyleFile(t.Styles[i].file()); err != nil {
+				return Style{}, err
+			}
+			t.Styles[i] = inherited
+		}
+	}
+	return inherited, nil
+}
+
+// isInheritedColor reports whether the palette entry comes from the parent unchanged
+func (t Theme) isInheritedColor(name string) bool {
+	if t.parent == nil {
+		return false
+	}
+	hex, ok := t.parent.Palette[name]
+	return ok && t.Palette[name] == hex
+}
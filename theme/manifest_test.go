@@ -0,0 +1,170 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeThemes lays out themes in a temporary config folder, as maps of file names to contents
+func writeThemes(t *testing.T, themes map[string]map[string]string) {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, files := range themes {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for file, contents := range files {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	folder := ThemeConfigFolder
+	ThemeConfigFolder = root
+	t.Cleanup(func() { ThemeConfigFolder = folder })
+}
+
+func loadTheme(t *testing.T, name string) (Theme, error) {
+	t.Helper()
+	return Load(os.DirFS(filepath.Join(ThemeConfigFolder, name)), name)
+}
+
+func styleNames(styles []Style) []string {
+	var names []string
+	for _, style := range styles {
+		names = append(names, style.Name)
+	}
+	return names
+}
+
+func TestInherit(t *testing.T) {
+	writeThemes(t, map[string]map[string]string{
+		"base": {
+			PaletteFile:  "accent: FF0000\nmuted: 888888\n",
+			"Audio.yaml": "theme: base\nname: Audio\nfore: $accent\nfiletypes: [.mp3, .ogg]\n",
+			"Video.yaml": "theme: base\nname: Video\nfiletypes: [.mp4]\n",
+			"Code.yaml":  "theme: base\nname: Code\nfore: $muted\nfiletypes: [.go, .rs]\n",
+		},
+		"child": {
+			ManifestFile: "schema: 1\nextends: base\nremove:\n  styles: [Video]\n  filetypes: [.rs]\n",
+			PaletteFile:  "accent: 00FF00\n",
+			"Audio.yaml": "theme: child\nname: Audio\nfore: $accent\nbold: true\nfiletypes: [.mp3]\n",
+			"Docs.yaml":  "theme: child\nname: Docs\nfiletypes: [.md]\n",
+		},
+	})
+
+	child, err := loadTheme(t, "child")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := styleNames(child.Styles), []string{"Audio", "Code", "Docs"}; !slices.Equal(got, want) {
+		t.Fatalf("styles = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		source    StyleSource
+		fore      string
+		fileTypes []string
+	}{
+		{SourceOverridden, "00FF00", []string{".mp3"}},
+		{SourceInherited, "888888", []string{".go"}},
+		{SourceLocal, "", []string{".md"}},
+	}
+	for i, test := range tests {
+		style := child.Styles[i]
+		if source := style.Source(); source != test.source {
+			t.Errorf("%v: Source() = %v, want %v", style.Name, source, test.source)
+		}
+		if fore := style.ResolvedFore(); fore != test.fore {
+			t.Errorf("%v: ResolvedFore() = %q, want %q", style.Name, fore, test.fore)
+		}
+		if !slices.Equal(style.FileTypes, test.fileTypes) {
+			t.Errorf("%v: FileTypes = %v, want %v", style.Name, style.FileTypes, test.fileTypes)
+		}
+		if style.Theme != "child" {
+			t.Errorf("%v: Theme = %q, want %q", style.Name, style.Theme, "child")
+		}
+	}
+
+	if problems := child.Lint(); len(problems) != 0 {
+		t.Errorf("Lint() = %v, want no problems", problems)
+	}
+}
+
+func TestInheritChain(t *testing.T) {
+	writeThemes(t, map[string]map[string]string{
+		"base":   {"Code.yaml": "theme: base\nname: Code\nfore: 111111\nfiletypes: [.go]\n"},
+		"middle": {ManifestFile: "extends: base\n", "Docs.yaml": "theme: middle\nname: Docs\nfiletypes: [.md]\n"},
+		"top":    {ManifestFile: "extends: middle\norder: [Docs, Code]\n"},
+	})
+
+	top, err := loadTheme(t, "top")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := styleNames(top.Styles), []string{"Docs", "Code"}; !slices.Equal(got, want) {
+		t.Errorf("styles = %v, want %v", got, want)
+	}
+	for _, style := range top.Styles {
+		if style.Source() != SourceInherited {
+			t.Errorf("%v: Source() = %v, want %v", style.Name, style.Source(), SourceInherited)
+		}
+	}
+}
+
+func TestInheritErrors(t *testing.T) {
+	writeThemes(t, map[string]map[string]string{
+		"a":       {ManifestFile: "extends: b\n"},
+		"b":       {ManifestFile: "extends: a\n"},
+		"self":    {ManifestFile: "extends: self\n"},
+		"orphan":  {ManifestFile: "extends: missing\n"},
+		"invalid": {ManifestFile: "extends: [a, b]\n"},
+	})
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"a", "extends itself (a -> b -> a)"},
+		{"self", "extends itself (self -> self)"},
+		{"orphan", `extending "missing"`},
+		{"invalid", ManifestFile},
+	}
+
+	for _, test := range tests {
+		_, err := loadTheme(t, test.name)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("loading %q: error = %v, want it to mention %q", test.name, err, test.want)
+		}
+	}
+}
+
+func TestMigrateManifestInMemory(t *testing.T) {
+	writeThemes(t, map[string]map[string]string{
+		"old": {
+			"README.md":  "# Old Theme\n\nDefault Foreground: #FFFFFF\nDefault Background: #101010\n",
+			"Audio.yaml": "theme: old\nname: Audio\nfiletypes: [.mp3]\n",
+		},
+	})
+
+	old, err := GetTheme("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Manifest{Schema: ManifestSchema, Title: "Old Theme", Background: BackgroundDark, Fore: "FFFFFF", Back: "101010"}
+	if !reflect.DeepEqual(old.Manifest, want) {
+		t.Errorf("Manifest = %+v, want %+v", old.Manifest, want)
+	}
+	if _, err := os.Stat(filepath.Join(ThemeConfigFolder, "old", ManifestFile)); !os.IsNotExist(err) {
+		t.Errorf("loading the theme wrote %v (stat error: %v)", ManifestFile, err)
+	}
+}
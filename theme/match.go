@@ -0,0 +1,119 @@
+package theme
+
+import (
+	"strings"
+)
+
+// Colorizer resolves the sequence a theme gives to a file, following the same rules as GNU ls.
+// It's built from the theme's LS_COLORS value, so anything rendered with it matches `apply`.
+type Colorizer struct {
+	indicators map[string]string
+	suffixes   []suffix
+}
+
+// suffix is a single `*pattern=seq` entry, kept in LS_COLORS order
+type suffix struct {
+	pattern string
+	seq     string
+}
+
+// Colorizer builds a Colorizer from the theme's styles
+func (t Theme) Colorizer() (Colorizer, error) {
+	value, err := t.LSColors()
+	if err != nil {
+		return Colorizer{}, err
+	}
+	return ParseColorizer(value), nil
+}
+
+// defaultIndicators are the colors GNU ls falls back on for any code LS_COLORS leaves out
+var defaultIndicators = map[string]string{
+	"rs": "0",
+	"di": "01;34",
+	"ln": "01;36",
+	"pi": "33",
+	"so": "01;35",
+	"bd": "01;33",
+	"cd": "01;33",
+	"ex": "01;32",
+	"do": "01;35",
+	"su": "37;41",
+	"sg": "30;43",
+	"st": "37;44",
+	"ow": "34;42",
+	"tw": "30;42",
+}
+
+// ParseColorizer builds a Colorizer from an LS_COLORS value. Malformed entries are skipped.
+func ParseColorizer(value string) Colorizer {
+	c := Colorizer{indicators: make(map[string]string)}
+	for code, seq := range defaultIndicators {
+		c.indicators[code] = seq
+	}
+
+	for _, entry := range strings.Split(value, ":") {
+		key, seq, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			continue
+		}
+		if strings.HasPrefix(key, "*") {
+			c.suffixes = append(c.suffixes, suffix{pattern: key[1:], seq: seq})
+		} else {
+			c.indicators[key] = seq
+		}
+	}
+
+	return c
+}
+
+// Indicator returns the sequence for a two-letter code such as `di` or `ex`. Like GNU ls,
+// a code is only considered colored if it's set to something other than `0` or `00`.
+func (c Colorizer) Indicator(code string) (string, bool) {
+	seq := c.indicators[code]
+	if seq == "" || seq == "0" || seq == "00" {
+		return "", false
+	}
+	return seq, true
+}
+
+// Suffix returns the sequence of the entry matching the end of name. Later entries win,
+// and case-sensitive matches are preferred over case-insensitive ones, as GNU ls does.
+func (c Colorizer) Suffix(name string) (string, bool) {
+	for i := len(c.suffixes) - 1; i >= 0; i-- {
+		if strings.HasSuffix(name, c.suffixes[i].pattern) {
+			return c.suffixes[i].seq, true
+		}
+	}
+
+	lower := strings.ToLower(name)
+	for i := len(c.suffixes) - 1; i >= 0; i-- {
+		if strings.HasSuffix(lower, strings.ToLower(c.suffixes[i].pattern)) {
+			return c.suffixes[i].seq, true
+		}
+	}
+
+	return "", false
+}
+
+// Resolve returns the sequence for a file classified as code, and whether there's one to
+// write at all. Regular files (`fi`) are matched against the suffix entries first, falling
+// back to `fi` itself. Unlike Indicator, codes explicitly set to `0` are still written.
+func (c Colorizer) Resolve(code, name string) (string, bool) {
+	if code == "fi" {
+		if seq, ok := c.Suffix(name); ok {
+			return seq, true
+		}
+	}
+	seq, ok := c.indicators[code]
+	return seq, ok
+}
+
+// Paint wraps text in the sequence code resolves to for name, or returns it untouched
+// if there's nothing to write
+func (c Colorizer) Paint(code, name, text string) string {
+	seq, ok := c.Resolve(code, name)
+	if !ok {
+		return text
+	}
+	return "\x1b[" + seq + "m" + text + "\x1b[" + c.indicators["rs"] + "m"
+}
@@ -0,0 +1,68 @@
+package theme
+
+import "testing"
+
+func TestColorizerSuffix(t *testing.T) {
+	c := ParseColorizer("*.gz=31:*.tar.gz=32:*.GZ=33:*.jpg=34:*.JPG=35:*.jpg=36:*Makefile=37:bad")
+
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"a.gz", "31", true},     // the later *.tar.gz doesn't match, so *.gz does
+		{"a.tar.gz", "32", true}, // later entries win over earlier ones
+		{"a.GZ", "33", true},     // a case-sensitive match beats the case-insensitive *.gz
+		{"a.Gz", "33", true},     // falling back to case-insensitive, the later *.GZ wins
+		{"a.jpg", "36", true},    // repeated entries keep the last one
+		{"a.JPG", "35", true},    // the exact case wins over the later case-insensitive match
+		{"Makefile", "37", true}, // filename entries are suffixes too
+		{"GNUmakefile", "37", true},
+		{"a.txt", "", false},
+	}
+
+	for _, test := range tests {
+		got, ok := c.Suffix(test.name)
+		if got != test.want || ok != test.ok {
+			t.Errorf("Suffix(%q) = %q, %v, want %q, %v", test.name, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestColorizerIndicators(t *testing.T) {
+	c := ParseColorizer("di=01;36:ex=0:pi=00:*.sh=32:")
+
+	tests := []struct {
+		code, name string
+		indicator  string
+		resolved   string
+		ok         bool
+	}{
+		{"di", "src", "01;36", "01;36", true},  // set by the theme
+		{"ln", "link", "01;36", "01;36", true}, // GNU's default
+		{"ex", "run", "", "0", true},           // explicitly uncolored, but still written
+		{"pi", "fifo", "", "00", true},
+		{"fi", "run.sh", "", "32", true}, // regular files are matched by suffix
+		{"fi", "notes", "", "", false},
+		{"mh", "hardlink", "", "", false},
+	}
+
+	for _, test := range tests {
+		if got, _ := c.Indicator(test.code); got != test.indicator {
+			t.Errorf("Indicator(%q) = %q, want %q", test.code, got, test.indicator)
+		}
+		if got, ok := c.Resolve(test.code, test.name); got != test.resolved || ok != test.ok {
+			t.Errorf("Resolve(%q, %q) = %q, %v, want %q, %v", test.code, test.name, got, ok, test.resolved, test.ok)
+		}
+	}
+}
+
+func TestColorizerPaint(t *testing.T) {
+	c := ParseColorizer("rs=0:di=01;34:")
+	if got, want := c.Paint("di", "src", "src"), "\x1b[01;34msrc\x1b[0m"; got != want {
+		t.Errorf("Paint() = %q, want %q", got, want)
+	}
+	if got := c.Paint("fi", "notes", "notes"); got != "notes" {
+		t.Errorf("Paint() = %q, want it untouched", got)
+	}
+}
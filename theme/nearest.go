@@ -0,0 +1,123 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+)
+
+// Matcher picks which of the xterm-256 colors is nearest to a hex code
+type Matcher string
+
+const (
+	// MatchRGB is termenv's own match, which snaps each channel to the color cube
+	MatchRGB Matcher = "rgb"
+	// MatchCIEDE2000 picks the color with the smallest CIEDE2000 difference
+	MatchCIEDE2000 Matcher = "ciede2000"
+	// MatchOkLab picks the color closest in the OkLab color space
+	MatchOkLab Matcher = "oklab"
+)
+
+// Matchers lists every matcher accepted by ParseMatcher
+var Matchers = []Matcher{MatchRGB, MatchCIEDE2000, MatchOkLab}
+
+// ParseMatcher checks that name is a matcher stylish knows about
+func ParseMatcher(name string) (Matcher, error) {
+	for _, matcher := range Matchers {
+		if strings.EqualFold(name, string(matcher)) {
+			return matcher, nil
+		}
+	}
+
+	names := make([]string, len(Matchers))
+	for i, matcher := range Matchers {
+		names[i] = string(matcher)
+	}
+	return "", fmt.Errorf("unknown matcher %q, expected one of: %v", name, strings.Join(names, ", "))
+}
+
+// xtermColors are colors 16 to 255 of the xterm-256 palette: the 6x6x6 color cube, then the
+// grayscale ramp. The first 16 are left out, as every terminal picks its own.
+var xtermColors = func() [240]colorful.Color {
+	var colors [240]colorful.Color
+	levels := [6]float64{0, 95, 135, 175, 215, 255}
+	for i := range 216 {
+		colors[i] = colorful.Color{R: levels[i/36] / 255, G: levels[i/6%6] / 255, B: levels[i%6] / 255}
+	}
+	for i := range 24 {
+		gray := float64(8+10*i) / 255
+		colors[216+i] = colorful.Color{R: gray, G: gray, B: gray}
+	}
+	return colors
+}()
+
+// xtermOkLab are xtermColors converted to OkLab ahead of time
+var xtermOkLab = func() [240][3]float64 {
+	var colors [240][3]float64
+	for i, color := range xtermColors {
+		colors[i] = okLab(color)
+	}
+	return colors
+}()
+
+// Nearest256 is the xterm-256 color the matcher picks for a hex code
+func (m Matcher) Nearest256(hex string) termenv.ANSI256Color {
+	color := hexColor(hex)
+
+	var distance func(i int) float64
+	switch m {
+	case MatchCIEDE2000:
+		distance = func(i int) float64 { return color.DistanceCIEDE2000(xtermColors[i]) }
+	case MatchOkLab:
+		lab := okLab(color)
+		distance = func(i int) float64 {
+			x := xtermOkLab[i]
+			return math.Sqrt((lab[0]-x[0])*(lab[0]-x[0]) + (lab[1]-x[1])*(lab[1]-x[1]) + (lab[2]-x[2])*(lab[2]-x[2]))
+		}
+	default:
+		return termenv.ANSI256.Convert(HexToRGB(hex)).(termenv.ANSI256Color)
+	}
+
+	nearest, nearestDistance := 0, math.Inf(1)
+	for i := range xtermColors {
+		if d := distance(i); d < nearestDistance {
+			nearest, nearestDistance = i, d
+		}
+	}
+	return termenv.ANSI256Color(16 + nearest)
+}
+
+// okLab converts a color to Björn Ottosson's OkLab, as L, a and b
+func okLab(c colorful.Color) [3]float64 {
+	r, g, b := c.LinearRgb()
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	return [3]float64{
+		0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// WithMatcher returns a copy of the style whose colors are matched to the xterm-256 palette with
+// the given matcher when downsampled. MatchRGB is used until one is picked.
+func (s Style) WithMatcher(matcher Matcher) Style {
+	s.matcher = matcher
+	return s
+}
+
+// WithMatcher returns a copy of the theme with every style's matcher set, like Style.WithMatcher
+func (t Theme) WithMatcher(matcher Matcher) Theme {
+	styles := make([]Style, len(t.Styles))
+	for i, style := range t.Styles {
+		styles[i] = style.WithMatcher(matcher)
+	}
+	t.Styles = styles
+	return t
+}
@@ -0,0 +1,70 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestNearest256(t *testing.T) {
+	tests := []struct {
+		hex                   string
+		rgb, ciede2000, okLab termenv.ANSI256Color
+	}{
+		// Colors in the palette are matched to themselves, except by rgb on the grayscale ramp
+		{"FF0000", 196, 196, 196},
+		{"5F87AF", 67, 67, 67},
+		{"000000", 16, 16, 16},
+		{"FFFFFF", 231, 231, 231},
+		{"808080", 102, 244, 244},
+		{"3A3A3A", 59, 237, 237},
+		// Between palette colors, the perceptual matchers can disagree with rgb and each other
+		{"EF476F", 203, 197, 197},
+		{"C06080", 132, 168, 132},
+		{"06D6A0", 43, 43, 43},
+	}
+
+	for _, test := range tests {
+		for matcher, want := range map[Matcher]termenv.ANSI256Color{
+			MatchRGB:       test.rgb,
+			MatchCIEDE2000: test.ciede2000,
+			MatchOkLab:     test.okLab,
+		} {
+			if got := matcher.Nearest256(test.hex); got != want {
+				t.Errorf("%v.Nearest256(%v) = %d, want %d", matcher, test.hex, got, want)
+			}
+		}
+	}
+}
+
+func TestNearest256IsClosest(t *testing.T) {
+	for _, hex := range []string{"EF476F", "FFD166", "06D6A0", "118AB2", "073B4C", "C06080", "7F7F7F"} {
+		color := hexColor(hex)
+		ciede := MatchCIEDE2000.Nearest256(hex)
+		okLabPick := MatchOkLab.Nearest256(hex)
+
+		for i := range xtermColors {
+			if color.DistanceCIEDE2000(xtermColors[i]) < color.DistanceCIEDE2000(xtermColors[ciede-16]) {
+				t.Errorf("ciede2000.Nearest256(%v) = %d, but %d is closer", hex, ciede, i+16)
+			}
+			if okLabDistance(okLab(color), xtermOkLab[i]) < okLabDistance(okLab(color), xtermOkLab[okLabPick-16]) {
+				t.Errorf("oklab.Nearest256(%v) = %d, but %d is closer", hex, okLabPick, i+16)
+			}
+		}
+	}
+}
+
+func okLabDistance(a, b [3]float64) float64 {
+	return (a[0]-b[0])*(a[0]-b[0]) + (a[1]-b[1])*(a[1]-b[1]) + (a[2]-b[2])*(a[2]-b[2])
+}
+
+func TestParseMatcher(t *testing.T) {
+	for _, name := range []string{"rgb", "CIEDE2000", "OkLab"} {
+		if _, err := ParseMatcher(name); err != nil {
+			t.Errorf("ParseMatcher(%q) error: %v", name, err)
+		}
+	}
+	if _, err := ParseMatcher("lab"); err == nil {
+		t.Error(`ParseMatcher("lab") didn't return an error`)
+	}
+}
@@ -0,0 +1,274 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PaletteFile is the file inside of a theme's folder that holds its named colors
+const PaletteFile = "palette.yaml"
+
+// PaletteRefPrefix marks a style's color as a reference to a palette entry (ex: `$accent`)
+const PaletteRefPrefix = "$"
+
+// Palette maps the names of a theme's shared colors to their hex codes.
+// Every style of a theme shares the same map, so changing an entry recolors them all.
+type Palette map[string]string
+
+// Names returns the palette's entry names in alphabetical order
+func (p Palette) Names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsPaletteRef reports whether a style color refers to a palette entry rather than being a hex code
+func IsPaletteRef(color string) bool {
+	return strings.HasPrefix(color, PaletteRefPrefix)
+}
+
+// ValidPaletteName checks that name can be used as a palette entry and referenced from a style
+func ValidPaletteName(name string) error {
+	if name == "" {
+		return errors.New("palette names can't be empty")
+	}
+	if strings.ContainsAny(name, " \t\n:#"+PaletteRefPrefix) {
+		return fmt.Errorf("palette name %q can't contain whitespace, ':', '#' or '%v'", name, PaletteRefPrefix)
+	}
+	return nil
+}
+
+// loadPalette reads the palette file from the root of fsys. Themes without one get an empty palette.
+func loadPalette(fsys fs.FS) (Palette, error) {
+	palette := make(Palette)
+
+	file, err := fsys.Open(PaletteFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return palette, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := yaml.NewDecoder(file).Decode(&palette); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%v: %w", PaletteFile, err)
+	}
+	if palette == nil {
+		palette = make(Palette)
+	}
+
+	return palette, nil
+}
+
+// ResolveColor turns one of the style's colors into a hex code, looking up palette references.
+// Empty colors stay empty.
+func (s Style) ResolveColor(color string) (string, error) {
+	if !IsPaletteRef(color) {
+		return color, nil
+	}
+
+	name := strings.TrimPrefix(color, PaletteRefPrefix)
+	hex, ok := s.palette[name]
+	if !ok {
+		return "", fmt.Errorf("palette color %q isn't defined", name)
+	}
+	return hex, nil
+}
+
+// ValidColor checks that color can be used as one of the style's colors: empty for the
+// terminal's default, a hex code, or a reference to a palette color that's defined
+func (s Style) ValidColor(color string) error {
+	switch {
+	case color == "":
+		return nil
+	case IsPaletteRef(color):
+		_, err := s.ResolveColor(color)
+		return err
+	}
+	return ValidHexCode(color)
+}
+
+// ResolvedFore is the style's foreground as a hex code, or empty if it's unset or can't be resolved
+func (s Style) ResolvedFore() string {
+	hex, _ := s.ResolveColor(s.Fore)
+	return hex
+}
+
+// ResolvedBack is the style's background as a hex code, or empty if it's unset or can't be resolved
+func (s Style) ResolvedBack() string {
+	hex, _ := s.ResolveColor(s.Back)
+	return hex
+}
+
+// NewStyle creates a new style in the theme that shares the theme's palette
+func (t Theme) NewStyle(styleName string) Style {
+	style := NewStyle(t.Name, styleName)
+	style.palette = t.Palette
+	return style
+}
+
+// SavePalette writes the theme's palette into its folder. An empty palette removes the file.
+// Colors inherited unchanged from the theme's parent are left for the parent to define.
+func (t Theme) SavePalette() error {
+	path := filepath.Join(ThemeConfigFolder, t.Name, PaletteFile)
+
+	own := make(Palette, len(t.Palette))
+	for name, hex := range t.Palette {
+		if !t.isInheritedColor(name) {
+			own[name] = hex
+		}
+	}
+
+	if len(own) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("theme %q: removing %v: %w", t.Name, PaletteFile, err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("theme %q: %v: %w", t.Name, PaletteFile, err)
+	}
+	defer file.Close()
+
+	if err := yaml.NewEncoder(file).Encode(own); err != nil {
+		return fmt.Errorf("theme %q: %v: %w", t.Name, PaletteFile, err)
+	}
+	return nil
+}
+
+// PaletteUsers lists the names of every style referring to the palette entry
+func (t Theme) PaletteUsers(name string) []string {
+	var users []string
+	for _, style := range t.Styles {
+		for _, color := range style.colors() {
+			if *color == PaletteRefPrefix+name {
+				users = append(users, style.Name)
+				break
+			}
+		}
+	}
+	return users
+}
+
+// SetPaletteColor adds or recolors a palette entry and saves the palette.
+// Every style referring to the entry picks up the new color.
+func (t *Theme) SetPaletteColor(name, hex string) error {
+	if err := ValidPaletteName(name); err != nil {
+		return err
+	}
+	if err := ValidHexCode(hex); err != nil {
+		return err
+	}
+	if t.Palette == nil {
+		t.Palette = make(Palette)
+	}
+
+	t.Palette[name] = hex
+	return t.SavePalette()
+}
+
+// RenamePaletteColor renames a palette entry, rewriting and saving every style that refers to it
+func (t *Theme) RenamePaletteColor(oldName, newName string) error {
+	hex, ok := t.Palette[oldName]
+	if !ok {
+		return fmt.Errorf("theme %q: palette color %q isn't defined", t.Name, oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if err := t.checkOwnColor(oldName); err != nil {
+		return err
+	}
+	if err := ValidPaletteName(newName); err != nil {
+		return err
+	}
+	if _, exists := t.Palette[newName]; exists {
+		return fmt.Errorf("theme %q: palette color %q already exists", t.Name, newName)
+	}
+
+	delete(t.Palette, oldName)
+	t.Palette[newName] = hex
+
+	if err := t.replacePaletteRefs(PaletteRefPrefix+oldName, PaletteRefPrefix+newName); err != nil {
+		return err
+	}
+	return t.SavePalette()
+}
+
+// RemovePaletteColor removes a palette entry. Styles that referred to it keep its color as a plain hex code.
+func (t *Theme) RemovePaletteColor(name string) error {
+	hex, ok := t.Palette[name]
+	if !ok {
+		return fmt.Errorf("theme %q: palette color %q isn't defined", t.Name, name)
+	}
+	if err := t.checkOwnColor(name); err != nil {
+		return err
+	}
+
+	if err := t.replacePaletteRefs(PaletteRefPrefix+name, hex); err != nil {
+		return err
+	}
+
+	delete(t.Palette, name)
+	return t.SavePalette()
+}
+
+// checkOwnColor makes sure a palette entry isn't defined by the theme's parent, which would
+// bring it straight back the next time the theme is loaded
+func (t Theme) checkOwnColor(name string) error {
+	if t.parent == nil {
+		return nil
+	}
+	if _, ok := t.parent.Palette[name]; ok {
+		return fmt.Errorf("theme %q: palette color %q comes from %q, so it can only be recolored", t.Name, name, t.parent.Name)
+	}
+	return nil
+}
+
+// replacePaletteRefs swaps every style color equal to from with to, saving the styles that changed
+func (t *Theme) replacePaletteRefs(from, to string) error {
+	for i := range t.Styles {
+		style := &t.Styles[i]
+
+		changed := false
+		for _, color := range style.colors() {
+			if *color == from {
+				*color = to
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		if err := style.SaveStyle(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithPalette returns a copy of the theme whose styles resolve their colors from p instead
+func (t Theme) WithPalette(p Palette) Theme {
+	styles := make([]Style, len(t.Styles))
+	for i, style := range t.Styles {
+		style.palette = p
+		styles[i] = style
+	}
+	t.Styles = styles
+	t.Palette = p
+	return t
+}
@@ -0,0 +1,153 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// The color vision deficiencies a theme can be simulated with
+const (
+	Protanopia    = "protanopia"    // No red cones
+	Deuteranopia  = "deuteranopia"  // No green cones
+	Tritanopia    = "tritanopia"    // No blue cones
+	Achromatopsia = "achromatopsia" // No color vision at all
+)
+
+// Simulations lists every deficiency accepted by ParseSimulation
+var Simulations = []string{Protanopia, Deuteranopia, Tritanopia, Achromatopsia}
+
+// MinSimulatedDistance is the default CIEDE2000 distance below which two styles look alike.
+// Around 2 is barely noticeable side by side, and 10 is easily told apart at a glance.
+const MinSimulatedDistance = 10.0
+
+// simulationMatrices are the full severity matrices from Machado, Oliveira and Fernandes (2009),
+// which act on linear RGB
+var simulationMatrices = map[string][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+// ParseSimulation checks that name is a deficiency stylish can simulate
+func ParseSimulation(name string) (string, error) {
+	for _, simulation := range Simulations {
+		if strings.EqualFold(name, simulation) {
+			return simulation, nil
+		}
+	}
+	return "", fmt.Errorf("unknown simulation %q, expected one of: %v", name, strings.Join(Simulations, ", "))
+}
+
+// SimulateColor returns how a hex code looks with the given deficiency. Unknown deficiencies
+// and invalid hex codes are returned as-is.
+func SimulateColor(hex, simulation string) string {
+	if ValidHexCode(hex) != nil {
+		return hex
+	}
+
+	r, g, b := hexColor(hex).LinearRgb()
+	var simulated colorful.Color
+	if simulation == Achromatopsia {
+		y := 0.2126*r + 0.7152*g + 0.0722*b
+		simulated = colorful.LinearRgb(y, y, y)
+	} else if m, ok := simulationMatrices[simulation]; ok {
+		simulated = colorful.LinearRgb(
+			m[0][0]*r+m[0][1]*g+m[0][2]*b,
+			m[1][0]*r+m[1][1]*g+m[1][2]*b,
+			m[2][0]*r+m[2][1]*g+m[2][2]*b,
+		)
+	} else {
+		return hex
+	}
+
+	return strings.ToUpper(strings.TrimPrefix(simulated.Clamped().Hex(), "#"))
+}
+
+// Simulate returns a copy of the style with its colors as they look with the given deficiency.
+// Only the style's own colors are changed, so pick a background with ForBackground first.
+func (s Style) Simulate(simulation string) Style {
+	if fore := s.ResolvedFore(); fore != "" {
+		s.Fore = SimulateColor(fore, simulation)
+		s.Fore256, s.Fore16 = nil, nil
+	}
+	if back := s.ResolvedBack(); back != "" {
+		s.Back = SimulateColor(back, simulation)
+		s.Back256, s.Back16 = nil, nil
+	}
+	return s
+}
+
+// Simulate returns a copy of the theme with every style's colors as they look with the given deficiency
+func (t Theme) Simulate(simulation string) Theme {
+	styles := make([]Style, len(t.Styles))
+	for i, style := range t.Styles {
+		styles[i] = style.Simulate(simulation)
+	}
+	t.Styles = styles
+	return t
+}
+
+// SimilarStyles is a pair of styles that are told apart normally, but look alike with a deficiency
+type SimilarStyles struct {
+	A, B     string
+	Distance float64 // CIEDE2000 distance between their simulated colors, from 0 to about 100
+}
+
+// SimilarStyles lists pairs of styles whose colors can be told apart normally, but fall below
+// threshold with the given deficiency. Colors a style leaves to the terminal are filled in
+// with defaultFore and defaultBack, and styles that set neither are skipped.
+func (t Theme) SimilarStyles(simulation, defaultFore, defaultBack string, threshold float64) []SimilarStyles {
+	type shown struct {
+		name             string
+		fore, back       colorful.Color
+		simFore, simBack colorful.Color
+	}
+
+	var styles []shown
+	for _, style := range t.Styles {
+		contrast, ok := style.Contrast(defaultFore, defaultBack)
+		if !ok {
+			continue
+		}
+		styles = append(styles, shown{
+			name:    style.Name,
+			fore:    hexColor(contrast.Fore),
+			back:    hexColor(contrast.Back),
+			simFore: hexColor(SimulateColor(contrast.Fore, simulation)),
+			simBack: hexColor(SimulateColor(contrast.Back, simulation)),
+		})
+	}
+
+	// Styles look alike when both their foregrounds and their backgrounds do. go-colorful's
+	// distances come out a hundred times smaller than the usual scale.
+	distance := func(fa, ba, fb, bb colorful.Color) float64 {
+		return max(fa.DistanceCIEDE2000(fb), ba.DistanceCIEDE2000(bb)) * 100
+	}
+
+	var similar []SimilarStyles
+	for i, a := range styles {
+		for _, b := range styles[i+1:] {
+			if distance(a.fore, a.back, b.fore, b.back) < threshold {
+				continue
+			}
+			if d := distance(a.simFore, a.simBack, b.simFore, b.simBack); d < threshold {
+				similar = append(similar, SimilarStyles{A: a.name, B: b.name, Distance: d})
+			}
+		}
+	}
+	return similar
+}
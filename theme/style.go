@@ -0,0 +1,256 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/muesli/termenv"
+)
+
+// Style is a set of attributes and colors applied to a group of filetypes
+type Style struct {
+	Theme string `yaml:"theme"`
+	Name  string `yaml:"name"`
+
+	Bold  bool `yaml:"bold"`
+	Under bool `yaml:"under"`
+	Blink bool `yaml:"blink"`
+
+	// Added after the original schema, so older files simply leave them unset
+	Dim      bool `yaml:"dim"`
+	Italic   bool `yaml:"italic"`
+	Reverse  bool `yaml:"reverse"`
+	Hidden   bool `yaml:"hidden"`
+	Strike   bool `yaml:"strike"`
+	Overline bool `yaml:"overline"`
+
+	Fore string `yaml:"fore"`
+	Back string `yaml:"back"`
+
+	// Fore256 and Back256 pin the xterm-256 index used in place of the nearest match to Fore and Back.
+	// Fore16 and Back16 pin the basic color (0-15) used on 16 color terminals.
+	Fore256 *int `yaml:"fore256,omitempty"`
+	Back256 *int `yaml:"back256,omitempty"`
+	Fore16  *int `yaml:"fore16,omitempty"`
+	Back16  *int `yaml:"back16,omitempty"`
+
+	// Light and Dark replace Fore and Back when the theme is applied to that kind of background
+	Light *Variant `yaml:"light,omitempty"`
+	Dark  *Variant `yaml:"dark,omitempty"`
+
+	FileTypes []string `yaml:"filetypes"`
+
+	// fileName is the name of the file the style was loaded from, without the extension
+	fileName string
+	// palette is shared with the style's theme, for resolving `$name` colors
+	palette Palette
+	// inherited is set when the theme's parent has a style of the same name,
+	// and local when the style has a file in the theme's own folder
+	inherited, local bool
+	// profile is the terminal color profile colors are encoded for
+	profile termenv.Profile
+	// matcher picks the xterm-256 colors used in place of truecolor ones
+	matcher Matcher
+}
+
+func (s *Style) ToggleBold() {
+	s.Bold = !s.Bold
+}
+
+func (s *Style) ToggleUnder() {
+	s.Under = !s.Under
+}
+
+func (s *Style) ToggleBlink() {
+	s.Blink = !s.Blink
+}
+
+func (s *Style) ToggleDim() {
+	s.Dim = !s.Dim
+}
+
+func (s *Style) ToggleItalic() {
+	s.Italic = !s.Italic
+}
+
+func (s *Style) ToggleReverse() {
+	s.Reverse = !s.Reverse
+}
+
+func (s *Style) ToggleHidden() {
+	s.Hidden = !s.Hidden
+}
+
+func (s *Style) ToggleStrike() {
+	s.Strike = !s.Strike
+}
+
+func (s *Style) ToggleOverline() {
+	s.Overline = !s.Overline
+}
+
+func (s *Style) SetFore(fore string) {
+	s.Fore = fore
+}
+
+func (s *Style) SetBack(back string) {
+	s.Back = back
+}
+
+func (s *Style) SetFiles(files string) {
+	s.FileTypes = make([]string, 0)
+	if files == "" {
+		return
+	}
+
+	// Ensure we're not saving any blankline filetypes
+	tempTypes := strings.Split(files, "\n")
+	for _, newType := range tempTypes {
+		if newType != "" {
+			s.FileTypes = append(s.FileTypes, newType)
+
+		}
+	}
+}
+
+func NewStyle(themeName, styleName string) Style {
+	return Style{
+		Theme:     themeName,
+		Name:      styleName,
+		FileTypes: make([]string, 0),
+	}
+}
+
+func CopyStyle(style Style, newName string) Style {
+	newStyle := NewStyle(style.Theme, newName)
+
+	newStyle.Bold = style.Bold
+	newStyle.Blink = style.Blink
+	newStyle.Under = style.Under
+	newStyle.Dim = style.Dim
+	newStyle.Italic = style.Italic
+	newStyle.Reverse = style.Reverse
+	newStyle.Hidden = style.Hidden
+	newStyle.Strike = style.Strike
+	newStyle.Overline = style.Overline
+	newStyle.Fore = style.Fore
+	newStyle.Back = style.Back
+	if style.Light != nil {
+		light := *style.Light
+		newStyle.Light = &light
+	}
+	if style.Dark != nil {
+		dark := *style.Dark
+		newStyle.Dark = &dark
+	}
+	newStyle.Fore256 = clonePin(style.Fore256)
+	newStyle.Back256 = clonePin(style.Back256)
+	newStyle.Fore16 = clonePin(style.Fore16)
+	newStyle.Back16 = clonePin(style.Back16)
+	newStyle.FileTypes = append(newStyle.FileTypes, style.FileTypes...)
+	newStyle.palette = style.palette
+	newStyle.profile = style.profile
+	newStyle.matcher = style.matcher
+
+	return newStyle
+}
+
+// file is the name of the style's file in its theme's folder. Styles are saved under their name,
+// but a hand-written file can be named something else.
+func (s Style) file() string {
+	if s.local && s.fileName != "" {
+		return s.fileName + ".yaml"
+	}
+	return s.Name + ".yaml"
+}
+
+// SaveStyle will validate the style's filetypes and write it to its theme's folder, over the file
+// it was loaded from. Saving an inherited style turns it into an override.
+func (s *Style) SaveStyle() error {
+	if _, err := s.ParseFileTypes(); err != nil {
+		return err
+	}
+
+	fileName := s.file()
+	path := filepath.Join(ThemeConfigFolder, s.Theme)
+	file, err := os.Create(filepath.Join(path, fileName))
+	if err != nil {
+		return fmt.Errorf("theme %q: style file %q: %w", s.Theme, fileName, err)
+	}
+
+	defer file.Close()
+
+	encoder := yaml.NewEncoder(file)
+	err = encoder.Encode(s)
+	if err != nil {
+		return fmt.Errorf("theme %q: style file %q: %w", s.Theme, fileName, err)
+	}
+
+	s.local = true
+	return nil
+}
+
+func (s Style) GetDirColorBlock() string {
+	if len(s.FileTypes) < 1 {
+		return ""
+	}
+
+	outStr := " # " + s.Name + "\n\n"
+
+	styleStr := s.Sequence()
+
+	for _, file := range s.FileTypes {
+		parsed, err := ParseFileType(file)
+		// dircolors has no notion of eza's UI elements, and can't take invalid entries
+		if err != nil || parsed.DirColorsKey() == "" {
+			continue
+		}
+		outStr += fmt.Sprintf("%v %v\n", parsed.DirColorsKey(), styleStr)
+	}
+
+	return outStr + "\n"
+}
+
+// Sequence returns the SGR parameters (ex: `1;38;2;239;71;111`) for the style's attributes and colors
+func (s Style) Sequence() string {
+	styleStr := ""
+
+	// Attributes are emitted in ascending SGR order
+	attrs := []struct {
+		on   bool
+		code string
+	}{
+		{s.Bold, "1"},
+		{s.Dim, "2"},
+		{s.Italic, "3"},
+		{s.Under, "4"},
+		{s.Blink, "5"},
+		{s.Reverse, "7"},
+		{s.Hidden, "8"},
+		{s.Strike, "9"},
+		{s.Overline, "53"},
+	}
+
+	for _, attr := range attrs {
+		if attr.on {
+			styleStr += attr.code + ";"
+		}
+	}
+
+	if fore := s.DownsampledFore(s.profile); fore != nil {
+		if seq := fore.Sequence(false); seq != "" {
+			styleStr += seq + ";"
+		}
+	}
+	if back := s.DownsampledBack(s.profile); back != nil {
+		if seq := back.Sequence(true); seq != "" {
+			styleStr += seq + ";"
+		}
+	}
+
+	return strings.TrimSuffix(styleStr, ";")
+}
@@ -0,0 +1,342 @@
+// Package theme is the data model behind stylish. It loads and saves themes
+// and their styles as YAML, converts colors, and renders themes into LS_COLORS.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/charmbracelet/log"
+)
+
+var UserConfig, _ = os.UserConfigDir()
+var ThemeConfigFolder = filepath.Join(UserConfig, "stylish")
+
+// Theme represents a collection of Styles
+type Theme struct {
+	Name     string
+	Path     string
+	Styles   []Style
+	Palette  Palette
+	Manifest Manifest
+
+	// parent is the theme named by the manifest's `extends`, if there is one
+	parent *Theme
+}
+
+// GetAllThemes will return a slice containing all Themes. A theme that fails to load is left
+// out, and its error is joined with any others while the rest of the themes keep loading.
+func GetAllThemes() ([]Theme, error) {
+	log.Debug("Trying to get all themes\n")
+	var outThemes []Theme
+	var errs []error
+
+	dir, err := os.ReadDir(ThemeConfigFolder)
+	if err != nil {
+		return nil, fmt.Errorf("reading theme folder %v: %w", ThemeConfigFolder, err)
+	}
+
+	for _, thing := range dir {
+		if thing.IsDir() {
+			log.Debugf("Dir found %v\n", thing.Name())
+			theme, err := GetTheme(thing.Name())
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			outThemes = append(outThemes, theme)
+		}
+	}
+
+	return outThemes, errors.Join(errs...)
+}
+
+// GetTheme will get the theme of a given name from the config folder. If the provided name
+// doesn't exist, a folder for that theme will be created.
+func GetTheme(name string) (Theme, error) {
+	if name == "" {
+		return Theme{}, errors.New("tried to create a theme with an empty name")
+	}
+
+	path := filepath.Join(ThemeConfigFolder, name)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.Mkdir(path, 0755); err != nil {
+			return Theme{Name: name, Path: path}, fmt.Errorf("theme %q: creating folder: %w", name, err)
+		}
+	}
+
+	log.Debugf("Theme created: %v", name)
+
+	fsys := os.DirFS(path)
+	outTheme, err := Load(fsys, name)
+	outTheme.Path = path
+	return outTheme, err
+}
+
+// Load will read a theme of the given name from the root of fsys.
+// The returned theme has no Path, so saving its styles still targets the config folder.
+// Themes that extend another have the other theme's styles merged in from the config folder.
+func Load(fsys fs.FS, name string) (Theme, error) {
+	return load(fsys, name, nil)
+}
+
+func load(fsys fs.FS, name string, chain []string) (Theme, error) {
+	outTheme := Theme{Name: name}
+
+	manifest, err := loadManifest(fsys)
+	if err != nil {
+		return outTheme, fmt.Errorf("theme %q: %w", name, err)
+	}
+	outTheme.Manifest = manifest
+	outTheme.migrateManifest(fsys)
+
+	palette, err := loadPalette(fsys)
+	if err != nil {
+		return outTheme, fmt.Errorf("theme %q: %w", name, err)
+	}
+	outTheme.Palette = palette
+
+	styles, err := outTheme.loadStyles(fsys)
+	if err != nil {
+		return outTheme, err
+	}
+	outTheme.Styles = styles
+
+	if manifest.Extends != "" {
+		parent, err := outTheme.loadParent(append(chain, name))
+		if err != nil {
+			return outTheme, err
+		}
+		outTheme.inherit(parent)
+	}
+	outTheme.sortStyles()
+
+	return outTheme, nil
+}
+
+// LoadStyles will load all of the styles for a given theme from its folder
+func (t Theme) LoadStyles() ([]Style, error) {
+	return t.loadStyles(os.DirFS(t.Path))
+}
+
+func (t Theme) loadStyles(fsys fs.FS) ([]Style, error) {
+	var outStyles []Style
+
+	log.Debugf("Trying to load styles for %v", t.Name)
+	dir, err := fs.ReadDir(fsys, ".")
+
+	if err != nil {
+		return nil, fmt.Errorf("theme %q: %w", t.Name, err)
+	}
+
+	for _, thing := range dir {
+		log.Debugf("- Thing found: %v", thing.Name())
+		if !thing.IsDir() && strings.HasSuffix(thing.Name(), ".yaml") &&
+			thing.Name() != PaletteFile && thing.Name() != ManifestFile {
+			style, err := t.loadStyleFile(fsys, thing.Name())
+			if err != nil {
+				return nil, fmt.Errorf("theme %q: style file %q: %w", t.Name, thing.Name(), err)
+			}
+
+			outStyles = append(outStyles, style)
+		}
+	}
+
+	return outStyles, nil
+}
+
+// loadStyleFile will decode a single style file from fsys.
+// Empty files result in a new, blank style named after the file.
+func (t Theme) loadStyleFile(fsys fs.FS, fileName string) (Style, error) {
+	name := strings.TrimSuffix(path.Base(fileName), ".yaml")
+
+	styleFile, err := fsys.Open(fileName)
+	if err != nil {
+		return Style{}, err
+	}
+	defer styleFile.Close()
+
+	var outStyle *Style
+	decoder := yaml.NewDecoder(styleFile)
+	if err := decoder.Decode(&outStyle); err != nil && err != io.EOF {
+		return Style{}, err
+	}
+	if outStyle == nil {
+		style := t.NewStyle(name)
+		style.fileName = name
+		style.local = true
+		return style, nil
+	}
+
+	outStyle.fileName = name
+	outStyle.palette = t.Palette
+	outStyle.local = true
+	return *outStyle, nil
+}
+
+// RemoveStyle will remove the style with a given name from both the theme's list and from the file system.
+// Inherited styles are also added to the manifest's removals, so the parent's version doesn't come back.
+func (t *Theme) RemoveStyle(styleName string) error {
+	file := styleName + ".yaml"
+	newStyles := make([]Style, 0)
+	for _, s := range t.Styles {
+		if s.Name != styleName {
+			newStyles = append(newStyles, s)
+		} else {
+			file = s.file()
+		}
+	}
+	t.Styles = newStyles
+
+	if err := t.removeStyleFile(file); err != nil {
+		return err
+	}
+
+	changed := false
+	if _, inherited := t.inheritedStyle(styleName); inherited && !slices.Contains(t.Manifest.Remove.Styles, styleName) {
+		t.Manifest.Remove.Styles = append(t.Manifest.Remove.Styles, styleName)
+		changed = true
+	}
+	if i := slices.Index(t.Manifest.Order, styleName); i >= 0 {
+		t.Manifest.Order = slices.Delete(t.Manifest.Order, i, i+1)
+		changed = true
+	}
+
+	if changed {
+		return t.SaveManifest()
+	}
+	return nil
+}
+
+// removeStyleFile deletes one of the files in the theme's folder, if it's there
+func (t Theme) removeStyleFile(file string) error {
+	path := filepath.Join(ThemeConfigFolder, t.Name, file)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("theme %q: removing style file %q: %w", t.Name, file, err)
+	}
+	return nil
+}
+
+// DoesStyleExist reports whether the theme has a style with the given name
+func (t Theme) DoesStyleExist(styleName string) bool {
+
+	for _, s := range t.Styles {
+		if s.Name == styleName {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidStyleName checks that a new style could be saved under the given name
+func (t Theme) ValidStyleName(styleName string) error {
+	switch {
+	case strings.TrimSpace(styleName) == "":
+		return errors.New("style names can't be empty")
+	case strings.ContainsAny(styleName, `/\`):
+		return fmt.Errorf("style name %q can't contain slashes", styleName)
+	case strings.HasSuffix(styleName, ".yaml"):
+		return fmt.Errorf("style name %q shouldn't include the .yaml extension", styleName)
+	case t.DoesStyleExist(styleName):
+		return fmt.Errorf("theme %q already has a style named %q", t.Name, styleName)
+	}
+
+	// Case-insensitive filesystems would save over these files under any casing
+	for _, reserved := range []string{ManifestFile, PaletteFile} {
+		if strings.EqualFold(styleName+".yaml", reserved) {
+			return fmt.Errorf("style name %q is taken by the theme's %v", styleName, reserved)
+		}
+	}
+	return nil
+}
+
+// GetStyle returns the theme's style with the given name, which edits the theme's copy
+func (t *Theme) GetStyle(styleName string) (*Style, error) {
+	for i := range t.Styles {
+		if t.Styles[i].Name == styleName {
+			return &t.Styles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("theme %q has no style named %q", t.Name, styleName)
+}
+
+// AddStyle saves a new style into the theme. If the theme's manifest orders its styles,
+// the new style is applied last.
+func (t *Theme) AddStyle(style Style) error {
+	if err := t.ValidStyleName(style.Name); err != nil {
+		return err
+	}
+	if err := style.SaveStyle(); err != nil {
+		return err
+	}
+
+	t.Styles = append(t.Styles, style)
+	if len(t.Manifest.Order) > 0 {
+		return t.SetOrder(append(t.Manifest.Order, style.Name))
+	}
+	return nil
+}
+
+// RenameStyle moves one of the theme's own styles to a new name, keeping its place in the order.
+// Styles inherited from the theme's parent are named by the parent, so they can only be copied.
+func (t *Theme) RenameStyle(oldName, newName string) error {
+	style, err := t.GetStyle(oldName)
+	if err != nil {
+		return err
+	}
+	if style.Source() != SourceLocal {
+		return fmt.Errorf("theme %q: style %q comes from %q, so it can't be renamed", t.Name, oldName, t.Manifest.Extends)
+	}
+	if err := t.ValidStyleName(newName); err != nil {
+		return err
+	}
+
+	renamed := *style
+	renamed.Name = newName
+	renamed.fileName = newName
+	if err := renamed.SaveStyle(); err != nil {
+		return err
+	}
+	// The old file can already be named after the new name, if the style didn't match its file
+	if file := style.file(); file != renamed.file() {
+		if err := t.removeStyleFile(file); err != nil {
+			return err
+		}
+	}
+	*style = renamed
+
+	if i := slices.Index(t.Manifest.Order, oldName); i >= 0 {
+		t.Manifest.Order[i] = newName
+		return t.SaveManifest()
+	}
+	return nil
+}
+
+// GenerateDirColors will convert all of a theme's styles into an output file
+func (t Theme) GenerateDirColors() error {
+
+	path := filepath.Join(ThemeConfigFolder, t.Name)
+	file, err := os.Create(filepath.Join(path, ".dircolors"))
+	if err != nil {
+		return fmt.Errorf("theme %q: %w", t.Name, err)
+	}
+	defer file.Close()
+
+	for _, style := range t.Styles {
+		if _, err := file.WriteString(style.GetDirColorBlock()); err != nil {
+			return fmt.Errorf("theme %q: writing .dircolors: %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
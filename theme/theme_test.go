@@ -0,0 +1,113 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestValidStyleName(t *testing.T) {
+	th := Theme{Name: "test", Styles: []Style{{Name: "Audio"}}}
+
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"Video", true},
+		{"Go Code", true},
+		{"", false},
+		{"   ", false},
+		{"Audio", false},
+		{"a/b", false},
+		{`a\b`, false},
+		{"Video.yaml", false},
+		{"theme", false},
+		{"Theme", false},
+		{"palette", false},
+		{"PALETTE", false},
+		{"themes", true},
+	}
+
+	for _, test := range tests {
+		err := th.ValidStyleName(test.name)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidStyleName(%q) = %v, want valid: %v", test.name, err, test.valid)
+		}
+	}
+}
+
+// themeFiles lists the files in a theme's folder
+func themeFiles(t *testing.T, name string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(ThemeConfigFolder, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+	return files
+}
+
+// Styles in files that don't match their names are edited, renamed and removed through their file
+func TestMismatchedStyleFile(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(th *Theme) error
+		want []string
+	}{
+		{"save", func(th *Theme) error {
+			style, err := th.GetStyle("Audio")
+			if err != nil {
+				return err
+			}
+			style.Bold = true
+			return style.SaveStyle()
+		}, []string{"Video.yaml", "sound.yaml"}},
+		{"rename", func(th *Theme) error { return th.RenameStyle("Audio", "Music") }, []string{"Music.yaml", "Video.yaml"}},
+		{"rename to file", func(th *Theme) error { return th.RenameStyle("Audio", "sound") }, []string{"Video.yaml", "sound.yaml"}},
+		{"remove", func(th *Theme) error { return th.RemoveStyle("Audio") }, []string{"Video.yaml"}},
+	}
+
+	for _, test := range tests {
+		writeThemes(t, map[string]map[string]string{
+			"mismatched": {
+				"sound.yaml": "theme: mismatched\nname: Audio\nfiletypes: [.mp3]\n",
+				"Video.yaml": "theme: mismatched\nname: Video\nfiletypes: [.mp4]\n",
+			},
+		})
+
+		th, err := loadTheme(t, "mismatched")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := test.edit(&th); err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+		if got := themeFiles(t, "mismatched"); !slices.Equal(got, test.want) {
+			t.Errorf("%v: files = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRevertStyleFile(t *testing.T) {
+	writeThemes(t, map[string]map[string]string{
+		"base":  {"Audio.yaml": "theme: base\nname: Audio\nfiletypes: [.mp3]\n"},
+		"child": {ManifestFile: "extends: base\n", "sound.yaml": "theme: child\nname: Audio\nbold: true\nfiletypes: [.mp3]\n"},
+	})
+
+	child, err := loadTheme(t, "child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := child.RevertStyle("Audio"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := themeFiles(t, "child"), []string{ManifestFile}; !slices.Equal(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
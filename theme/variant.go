@@ -0,0 +1,94 @@
+package theme
+
+// Variant holds the colors a style uses in place of its own on one kind of background.
+// Empty colors fall back to the style's.
+type Variant struct {
+	Fore string `yaml:"fore,omitempty"`
+	Back string `yaml:"back,omitempty"`
+}
+
+// Variant returns the style's colors for the given background, or nil if it doesn't declare any
+func (s Style) Variant(background string) *Variant {
+	switch background {
+	case BackgroundLight:
+		return s.Light
+	case BackgroundDark:
+		return s.Dark
+	}
+	return nil
+}
+
+// SetVariantFore sets the foreground used on the given background. An empty background sets the style's own.
+func (s *Style) SetVariantFore(background, fore string) {
+	s.setVariant(background, func(v *Variant) { v.Fore = fore }, func() { s.Fore = fore })
+}
+
+// SetVariantBack sets the background color used on the given background. An empty background sets the style's own.
+func (s *Style) SetVariantBack(background, back string) {
+	s.setVariant(background, func(v *Variant) { v.Back = back }, func() { s.Back = back })
+}
+
+// setVariant applies set to a copy of the variant for background, since copies of a style share
+// their variants, and drops it once it's empty again. An empty background gets setBase instead.
+func (s *Style) setVariant(background string, set func(*Variant), setBase func()) {
+	var variant **Variant
+	switch background {
+	case BackgroundLight:
+		variant = &s.Light
+	case BackgroundDark:
+		variant = &s.Dark
+	default:
+		setBase()
+		return
+	}
+
+	var updated Variant
+	if *variant != nil {
+		updated = **variant
+	}
+	set(&updated)
+
+	if updated == (Variant{}) {
+		*variant = nil
+	} else {
+		*variant = &updated
+	}
+}
+
+// colors points at every color the style sets, including those of its variants
+func (s *Style) colors() []*string {
+	colors := []*string{&s.Fore, &s.Back}
+	for _, variant := range []*Variant{s.Light, s.Dark} {
+		if variant != nil {
+			colors = append(colors, &variant.Fore, &variant.Back)
+		}
+	}
+	return colors
+}
+
+// ForBackground returns a copy of the style with its colors for the given background swapped in
+func (s Style) ForBackground(background string) Style {
+	if variant := s.Variant(background); variant != nil {
+		// Pins are picked for the style's own colors, so they don't carry over to the variant's
+		if variant.Fore != "" {
+			s.Fore = variant.Fore
+			s.Fore256, s.Fore16 = nil, nil
+		}
+		if variant.Back != "" {
+			s.Back = variant.Back
+			s.Back256, s.Back16 = nil, nil
+		}
+	}
+	return s
+}
+
+// ForBackground returns a copy of the theme with every style's colors for the given background
+// swapped in. Themes applied without a background just use each style's own colors.
+func (t Theme) ForBackground(background string) Theme {
+	styles := make([]Style, len(t.Styles))
+	for i, style := range t.Styles {
+		styles[i] = style.ForBackground(background)
+	}
+	t.Styles = styles
+	return t
+}